@@ -0,0 +1,211 @@
+package sql
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/gopub/log"
+)
+
+// aggregateHolder scans result of aggregate function into dest. NULL is recorded instead of being scanned
+type aggregateHolder struct {
+	dest interface{}
+	null bool
+}
+
+func (h *aggregateHolder) Scan(src interface{}) error {
+	if src == nil {
+		h.null = true
+		return nil
+	}
+	if s, ok := h.dest.(Scanner); ok {
+		return s.Scan(src)
+	}
+	v := reflect.ValueOf(h.dest).Elem()
+	if !isNullableType(v.Type()) {
+		return fmt.Errorf("cannot scan %T into %v", src, v.Type())
+	}
+	return (&nullableHolder{v: v}).Scan(src)
+}
+
+// aggregate selects result of function fn on column into dest. It returns ErrNoRows if result is NULL
+func (q *Query) aggregate(ctx context.Context, fn, column string, dest interface{}) error {
+	if v := reflect.ValueOf(dest); v.Kind() != reflect.Ptr || v.IsNil() {
+		panic("dest must be a pointer")
+	}
+	query, args := q.scoped(q.table.info()).selectQuery(fn + "(" + q.table.dialect.QuoteIdent(column) + ")")
+	h := &aggregateHolder{dest: dest}
+	if err := q.table.queryRow(ctx, query, args).Scan(h); err != nil {
+		log.Error(err)
+		return err
+	}
+	if h.null {
+		return ErrNoRows
+	}
+	return nil
+}
+
+// Sum selects sum of column into dest which is a pointer to number or Scanner, e.g. *int64 to keep precision of big integers.
+// dest is set to zero if no row
+func (q *Query) Sum(column string, dest interface{}) error {
+	return q.SumContext(context.Background(), column, dest)
+}
+
+func (q *Query) SumContext(ctx context.Context, column string, dest interface{}) error {
+	err := q.aggregate(ctx, "SUM", column, dest)
+	if err == ErrNoRows {
+		v := reflect.ValueOf(dest).Elem()
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+	return err
+}
+
+// Avg returns average of column. It returns ErrNoRows if no row
+func (q *Query) Avg(column string) (float64, error) {
+	return q.AvgContext(context.Background(), column)
+}
+
+func (q *Query) AvgContext(ctx context.Context, column string) (float64, error) {
+	var avg float64
+	if err := q.aggregate(ctx, "AVG", column, &avg); err != nil {
+		return 0, err
+	}
+	return avg, nil
+}
+
+// Min selects minimum value of column into dest which is a pointer to number, string, time.Time or Scanner.
+// It returns ErrNoRows if no row
+func (q *Query) Min(column string, dest interface{}) error {
+	return q.MinContext(context.Background(), column, dest)
+}
+
+func (q *Query) MinContext(ctx context.Context, column string, dest interface{}) error {
+	return q.aggregate(ctx, "MIN", column, dest)
+}
+
+// Max selects maximum value of column into dest which is a pointer to number, string, time.Time or Scanner.
+// It returns ErrNoRows if no row
+func (q *Query) Max(column string, dest interface{}) error {
+	return q.MaxContext(context.Background(), column, dest)
+}
+
+func (q *Query) MaxContext(ctx context.Context, column string, dest interface{}) error {
+	return q.aggregate(ctx, "MAX", column, dest)
+}
+
+// Exists returns true if any row matches conditions. It stops at the first row
+func (q *Query) Exists() (bool, error) {
+	return q.ExistsContext(context.Background())
+}
+
+func (q *Query) ExistsContext(ctx context.Context) (bool, error) {
+	sq := *q.scoped(q.table.info())
+	sq.orderBy = nil
+	sq.limit = 1
+	query, args := sq.selectQuery("1")
+	var one int
+	err := q.table.queryRow(ctx, query, args).Scan(&one)
+	if err == ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		log.Error(err)
+		return false, err
+	}
+	return true, nil
+}
+
+// CountBy returns number of rows of each value of column. Values are converted as SelectMaps does, and NULL is nil
+func (q *Query) CountBy(column string) (map[interface{}]int64, error) {
+	return q.CountByContext(context.Background(), column)
+}
+
+func (q *Query) CountByContext(ctx context.Context, column string) (map[interface{}]int64, error) {
+	quoted := q.table.dialect.QuoteIdent(column)
+	sq := *q.scoped(q.table.info())
+	sq.groupBy = []string{quoted}
+	query, args := sq.selectQuery(quoted + ", COUNT(*)")
+	rows, err := q.table.query(ctx, query, args)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	m := make(map[interface{}]int64)
+	for rows.Next() {
+		var key interface{}
+		var n int64
+		if err = rows.Scan(&key, &n); err != nil {
+			log.Error(err)
+			return nil, err
+		}
+		key = normalizeValue(key, columnTypes[0].DatabaseTypeName())
+		// Slice can't be map key
+		if b, ok := key.([]byte); ok {
+			key = string(b)
+		}
+		m[key] = n
+	}
+	if err = rows.Err(); err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	return m, nil
+}
+
+func (t *Table) Sum(column string, dest interface{}, where interface{}, args ...interface{}) error {
+	return t.SumContext(context.Background(), column, dest, where, args...)
+}
+
+func (t *Table) SumContext(ctx context.Context, column string, dest interface{}, where interface{}, args ...interface{}) error {
+	return t.Where(where, args...).SumContext(ctx, column, dest)
+}
+
+func (t *Table) Avg(column string, where interface{}, args ...interface{}) (float64, error) {
+	return t.AvgContext(context.Background(), column, where, args...)
+}
+
+func (t *Table) AvgContext(ctx context.Context, column string, where interface{}, args ...interface{}) (float64, error) {
+	return t.Where(where, args...).AvgContext(ctx, column)
+}
+
+func (t *Table) Min(column string, dest interface{}, where interface{}, args ...interface{}) error {
+	return t.MinContext(context.Background(), column, dest, where, args...)
+}
+
+func (t *Table) MinContext(ctx context.Context, column string, dest interface{}, where interface{}, args ...interface{}) error {
+	return t.Where(where, args...).MinContext(ctx, column, dest)
+}
+
+func (t *Table) Max(column string, dest interface{}, where interface{}, args ...interface{}) error {
+	return t.MaxContext(context.Background(), column, dest, where, args...)
+}
+
+func (t *Table) MaxContext(ctx context.Context, column string, dest interface{}, where interface{}, args ...interface{}) error {
+	return t.Where(where, args...).MaxContext(ctx, column, dest)
+}
+
+// ExistsWhere returns true if any row matches conditions. Exists checks a record by primary key
+func (t *Table) ExistsWhere(where interface{}, args ...interface{}) (bool, error) {
+	return t.ExistsWhereContext(context.Background(), where, args...)
+}
+
+func (t *Table) ExistsWhereContext(ctx context.Context, where interface{}, args ...interface{}) (bool, error) {
+	return t.Where(where, args...).ExistsContext(ctx)
+}
+
+func (t *Table) CountBy(column string, where interface{}, args ...interface{}) (map[interface{}]int64, error) {
+	return t.CountByContext(context.Background(), column, where, args...)
+}
+
+func (t *Table) CountByContext(ctx context.Context, column string, where interface{}, args ...interface{}) (map[interface{}]int64, error) {
+	return t.Where(where, args...).CountByContext(ctx, column)
+}
@@ -0,0 +1,57 @@
+package sql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAggregateHolder(t *testing.T) {
+	var f float64
+	h := &aggregateHolder{dest: &f}
+	require.NoError(t, h.Scan([]byte("1.5")))
+	require.False(t, h.null)
+	require.Equal(t, 1.5, f)
+
+	h = &aggregateHolder{dest: &f}
+	require.NoError(t, h.Scan(nil))
+	require.True(t, h.null)
+
+	var s NullString
+	h = &aggregateHolder{dest: &s}
+	require.NoError(t, h.Scan("a"))
+	require.Equal(t, "a", s.String)
+}
+
+func TestQuery_Aggregate(t *testing.T) {
+	type Product struct {
+		ID    int `sql:"primary key"`
+		Name  string
+		Stock int64
+		Price float64
+	}
+	db := openSQLite(t)
+	require.NoError(t, db.CreateTable(&Product{}))
+	require.NoError(t, db.Insert(&Product{ID: 1, Name: "apple", Stock: 1 << 60, Price: 0.5}))
+	require.NoError(t, db.Insert(&Product{ID: 2, Name: "apple", Stock: 1, Price: 1.5}))
+	tbl := db.Table(&Product{})
+
+	var stock int64
+	require.NoError(t, tbl.Sum("stock", &stock, "name = ?", "apple"))
+	require.Equal(t, int64(1<<60+1), stock)
+	require.NoError(t, tbl.Sum("stock", &stock, "name = ?", "pear"))
+	require.Zero(t, stock)
+
+	avg, err := tbl.Avg("price", "")
+	require.NoError(t, err)
+	require.Equal(t, 1.0, avg)
+	_, err = tbl.Avg("price", "name = ?", "pear")
+	require.Equal(t, ErrNoRows, err)
+
+	var maxPrice float64
+	require.NoError(t, tbl.Max("price", &maxPrice, ""))
+	require.Equal(t, 1.5, maxPrice)
+
+	var invalid []int
+	require.Error(t, tbl.Min("price", &invalid, ""))
+}
@@ -0,0 +1,194 @@
+package sql
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/gopub/log"
+)
+
+// AutoMigrate creates table of record if it doesn't exist, otherwise adds missing columns and indexes.
+// Destructive differences, e.g. columns which are not in struct or have different types, are returned but not applied.
+// It's intended for development and test, use package migrate in production
+func (t *Table) AutoMigrate(record interface{}) ([]*SchemaDiff, error) {
+	return t.AutoMigrateContext(context.Background(), record)
+}
+
+func (t *Table) AutoMigrateContext(ctx context.Context, record interface{}) ([]*SchemaDiff, error) {
+	inspector, ok := t.dialect.(Inspector)
+	if !ok {
+		err := errors.New("dialect " + t.dialect.Name() + " doesn't support AutoMigrate")
+		log.Error(err)
+		return nil, err
+	}
+
+	columns, err := inspector.Columns(ctx, t.exe, t.name)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	if len(columns) == 0 {
+		return nil, t.CreateTableContext(ctx, record, IfNotExists)
+	}
+
+	typ := structType(record)
+	info := getColumnInfo(typ)
+	nameToColumn := make(map[string]*ColumnSchema, len(columns))
+	for _, c := range columns {
+		nameToColumn[strings.ToLower(c.Name)] = c
+	}
+
+	var diffs []*SchemaDiff
+	for _, name := range info.names {
+		c, ok := nameToColumn[name]
+		if !ok {
+			if name == info.aiName || IndexOfString(info.pkNames, name) >= 0 {
+				diffs = append(diffs, &SchemaDiff{Table: t.name, Column: name, Reason: "primary key column is missing"})
+				continue
+			}
+			diff, err := t.addColumn(ctx, typ, info, name)
+			if err != nil {
+				return nil, err
+			}
+			if diff != nil {
+				diffs = append(diffs, diff)
+			}
+			continue
+		}
+		delete(nameToColumn, name)
+
+		if declared := declaredType(t.dialect, typ, info, name); !inspector.EqualType(declared, c.Type) {
+			diffs = append(diffs, &SchemaDiff{Table: t.name, Column: name, Reason: "type " + c.Type + " is changed to " + declared})
+		}
+
+		// Primary key is NOT NULL implicitly
+		nullable := IndexOfString(info.nullableNames, name) >= 0
+		if c.Nullable != nullable && IndexOfString(info.pkNames, name) < 0 {
+			if nullable {
+				diffs = append(diffs, &SchemaDiff{Table: t.name, Column: name, Reason: "NOT NULL is removed"})
+			} else {
+				diffs = append(diffs, &SchemaDiff{Table: t.name, Column: name, Reason: "NOT NULL is added"})
+			}
+		}
+	}
+
+	for _, c := range columns {
+		if _, ok := nameToColumn[strings.ToLower(c.Name)]; ok {
+			diffs = append(diffs, &SchemaDiff{Table: t.name, Column: c.Name, Reason: "column is not in struct"})
+		}
+	}
+
+	indexes, err := inspector.Indexes(ctx, t.exe, t.name)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	for _, name := range info.indexNames {
+		index := indexName(t.name, name)
+		if IndexOfString(indexes, index) >= 0 {
+			continue
+		}
+		query := "CREATE INDEX " + t.dialect.QuoteIdent(index) + " ON " + t.dialect.QuoteIdent(t.name) +
+			" (" + t.dialect.QuoteIdent(name) + ")"
+		if _, err = t.exec(ctx, query, nil); err != nil {
+			log.Error(err)
+			return nil, err
+		}
+	}
+
+	// Unique column gets a unique index, as SQLite can't add UNIQUE column
+	uniqueColumns, err := inspector.UniqueColumns(ctx, t.exe, t.name)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	for _, name := range info.uniqueNames {
+		if IndexOfString(uniqueColumns, name) >= 0 {
+			continue
+		}
+		index := uniqueIndexName(t.name, name)
+		if IndexOfString(indexes, index) >= 0 {
+			diffs = append(diffs, &SchemaDiff{Table: t.name, Column: name, Reason: "index " + index + " is not unique"})
+			continue
+		}
+		query := "CREATE UNIQUE INDEX " + t.dialect.QuoteIdent(index) + " ON " + t.dialect.QuoteIdent(t.name) +
+			" (" + t.dialect.QuoteIdent(name) + ")"
+		if _, err = t.exec(ctx, query, nil); err != nil {
+			log.Error(err)
+			return nil, err
+		}
+	}
+	return diffs, nil
+}
+
+// addColumn adds column to table. NOT NULL column without default value is filled with zero value of its type,
+// or added as nullable if the type has no zero value literal, which is reported by the returned diff
+func (t *Table) addColumn(ctx context.Context, typ reflect.Type, info *columnInfo, name string) (*SchemaDiff, error) {
+	var diff *SchemaDiff
+	def := t.dialect.QuoteIdent(name) + " " + declaredType(t.dialect, typ, info, name)
+	value, hasDefault := info.defaults[name]
+	if IndexOfString(info.nullableNames, name) < 0 {
+		if !hasDefault {
+			value = zeroDefault(t.dialect, columnDef(typ, info, name))
+			hasDefault = len(value) > 0
+		}
+		if hasDefault {
+			def += " NOT NULL"
+		} else {
+			diff = &SchemaDiff{Table: t.name, Column: name, Reason: "column is added as nullable, as it has no default value"}
+		}
+	}
+	if hasDefault {
+		def += " DEFAULT " + value
+	}
+	_, err := t.exec(ctx, "ALTER TABLE "+t.dialect.QuoteIdent(t.name)+" ADD COLUMN "+def, nil)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	return diff, nil
+}
+
+// zeroDefault returns literal of zero value of column, or empty string if there's no literal, e.g. BLOB and JSON
+func zeroDefault(d Dialect, c *ColumnDef) string {
+	if c.JSON {
+		return ""
+	}
+	if c.Type == _timeType {
+		if d.Name() == PostgreSQL.Name() {
+			return "'0001-01-01 00:00:00+00'"
+		}
+		return "'0001-01-01 00:00:00'"
+	}
+	switch c.Type.Kind() {
+	case reflect.Bool:
+		return "FALSE"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "0"
+	case reflect.String:
+		return "''"
+	default:
+		return ""
+	}
+}
+
+// AutoMigrate migrates tables of records, see Table.AutoMigrate
+func (s *session) AutoMigrate(records ...interface{}) ([]*SchemaDiff, error) {
+	return s.AutoMigrateContext(context.Background(), records...)
+}
+
+func (s *session) AutoMigrateContext(ctx context.Context, records ...interface{}) ([]*SchemaDiff, error) {
+	var diffs []*SchemaDiff
+	for _, r := range records {
+		l, err := s.Table(r).AutoMigrateContext(ctx, r)
+		if err != nil {
+			return nil, err
+		}
+		diffs = append(diffs, l...)
+	}
+	return diffs, nil
+}
@@ -0,0 +1,83 @@
+package sql
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTable_AutoMigrate(t *testing.T) {
+	type Product struct {
+		ID int `sql:"primary key"`
+	}
+	db := openSQLite(t)
+	diffs, err := db.AutoMigrate(&Product{})
+	require.NoError(t, err)
+	require.Empty(t, diffs)
+	require.NoError(t, db.Insert(&Product{ID: 1}))
+
+	type NewProduct struct {
+		ID        int    `sql:"primary key"`
+		Code      string `sql:",unique"`
+		Price     float64
+		Available bool
+		CreatedAt time.Time
+		Data      []byte
+	}
+	diffs, err = db.Table("products").AutoMigrate(&NewProduct{})
+	require.NoError(t, err)
+	require.Len(t, diffs, 1)
+	require.Equal(t, "data", diffs[0].Column)
+
+	var p NewProduct
+	require.NoError(t, db.Table("products").Where("id = ?", 1).SelectOne(&p))
+	require.Empty(t, p.Code)
+	require.True(t, p.CreatedAt.IsZero())
+	require.Nil(t, p.Data)
+
+	// Unique index is created
+	require.NoError(t, db.Table("products").Insert(&NewProduct{ID: 2, Code: "a"}))
+	require.Error(t, db.Table("products").Insert(&NewProduct{ID: 3, Code: "a"}))
+
+	diffs, err = db.Table("products").AutoMigrate(&NewProduct{})
+	require.NoError(t, err)
+	require.Len(t, diffs, 1)
+	require.Equal(t, "NOT NULL is added", diffs[0].Reason)
+}
+
+func TestTable_AutoMigrate_Unique(t *testing.T) {
+	type Account struct {
+		ID    int `sql:"primary key"`
+		Email string
+		Phone string `sql:"unique"`
+	}
+	db := openSQLite(t)
+	require.NoError(t, db.CreateTable(&Account{}))
+	indexes, err := sqliteDialect{}.Indexes(context.Background(), db.db, "accounts")
+	require.NoError(t, err)
+
+	// Unique constraint of CREATE TABLE is kept
+	diffs, err := db.AutoMigrate(&Account{})
+	require.NoError(t, err)
+	require.Empty(t, diffs)
+	l, err := sqliteDialect{}.Indexes(context.Background(), db.db, "accounts")
+	require.NoError(t, err)
+	require.Equal(t, indexes, l)
+
+	// Existing column gets a unique index
+	type UniqueAccount struct {
+		ID    int    `sql:"primary key"`
+		Email string `sql:"unique"`
+		Phone string `sql:"unique"`
+	}
+	diffs, err = db.Table("accounts").AutoMigrate(&UniqueAccount{})
+	require.NoError(t, err)
+	require.Empty(t, diffs)
+	require.NoError(t, db.Table("accounts").Insert(&UniqueAccount{ID: 1, Email: "a", Phone: "1"}))
+	require.Error(t, db.Table("accounts").Insert(&UniqueAccount{ID: 2, Email: "a", Phone: "2"}))
+	columns, err := sqliteDialect{}.UniqueColumns(context.Background(), db.db, "accounts")
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"email", "phone"}, columns)
+}
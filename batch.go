@@ -0,0 +1,222 @@
+package sql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/gopub/log"
+)
+
+// BatchInsert inserts records in values with multi-row insert statements.
+// Records are split into chunks which don't exceed dialect's MaxParams.
+// Generated auto increment ids are written back into records if the dialect supports it, which excludes MySQL.
+// Version of versioned records is set to 1 if it's zero
+func (t *Table) BatchInsert(values interface{}) error {
+	return t.BatchInsertContext(context.Background(), values)
+}
+
+func (t *Table) BatchInsertContext(ctx context.Context, values interface{}) error {
+	l := reflect.ValueOf(values)
+	if l.Kind() == reflect.Ptr {
+		l = l.Elem()
+	}
+	if l.Kind() != reflect.Slice {
+		return errors.New("not slice")
+	}
+
+	n := l.Len()
+	for i := 0; i < n; {
+		v := getStructValue(sliceElem(l, i))
+		info := getColumnInfo(v.Type())
+		columns := insertColumns(v, info)
+		if len(columns) == 0 {
+			err := fmt.Errorf("no column to insert in %s", v.Type())
+			log.Error(err)
+			return err
+		}
+		maxRows := t.dialect.MaxParams() / len(columns)
+		if maxRows < 1 {
+			maxRows = 1
+		}
+
+		// Records in a chunk must have the same type and columns
+		aiZero := isAutoIncrementZero(v, info)
+		j := i + 1
+		for ; j < n && j-i < maxRows; j++ {
+			vj := getStructValue(sliceElem(l, j))
+			if vj.Type() != v.Type() || isAutoIncrementZero(vj, info) != aiZero {
+				break
+			}
+		}
+
+		if err := t.batchInsert(ctx, l.Slice(i, j), info, columns, aiZero); err != nil {
+			return err
+		}
+		i = j
+	}
+	return nil
+}
+
+func (t *Table) batchInsert(ctx context.Context, l reflect.Value, info *columnInfo, columns []string, aiZero bool) error {
+	n := l.Len()
+	records := make([]reflect.Value, n)
+	for i := 0; i < n; i++ {
+		records[i] = getStructValue(sliceElem(l, i))
+	}
+
+	// New records start at version 1, which is reverted if insert fails
+	var versioned []reflect.Value
+	if len(info.versionName) > 0 {
+		for _, r := range records {
+			if f := r.FieldByIndex(info.nameToIndex[info.versionName]); f.Int() == 0 {
+				f.SetInt(1)
+				versioned = append(versioned, f)
+			}
+		}
+	}
+
+	err := t.execBatchInsert(ctx, records, info, columns, aiZero)
+	if err != nil {
+		for _, f := range versioned {
+			f.SetInt(0)
+		}
+		return err
+	}
+	for _, r := range records {
+		track(r, info, info.names)
+	}
+	return nil
+}
+
+func (t *Table) execBatchInsert(ctx context.Context, records []reflect.Value, info *columnInfo, columns []string, aiZero bool) error {
+	n := len(records)
+	values := make([]interface{}, 0, n*len(columns))
+	for _, r := range records {
+		t.stampInsert(r, info)
+		for _, name := range columns {
+			fv, err := getFieldValueByName(r, info, name)
+			if err != nil {
+				log.Error(err)
+				return err
+			}
+			values = append(values, fv)
+		}
+	}
+
+	query := t.insertQuery(columns, n)
+	if !aiZero {
+		_, err := t.exec(ctx, query, values)
+		if err != nil {
+			log.Error(err)
+		}
+		return err
+	}
+
+	aiIndex := info.nameToIndex[info.aiName]
+	if returning := t.dialect.Returning(info.aiName); len(returning) > 0 {
+		rows, err := t.query(ctx, query+" "+returning, values)
+		if err != nil {
+			log.Error(err)
+			return err
+		}
+		defer rows.Close()
+		for i := 0; rows.Next() && i < n; i++ {
+			var id int64
+			if err = rows.Scan(&id); err != nil {
+				log.Error(err)
+				return err
+			}
+			records[i].FieldByIndex(aiIndex).SetInt(id)
+		}
+		if err = rows.Err(); err != nil {
+			log.Error(err)
+		}
+		return err
+	}
+
+	result, err := t.exec(ctx, query, values)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+	lastID, err := result.LastInsertId()
+	if err != nil {
+		// Records have been inserted, ids are just not available
+		log.Warn(err)
+		return nil
+	}
+	for i, r := range records {
+		if id, ok := t.dialect.BatchInsertID(lastID, i, n); ok {
+			r.FieldByIndex(aiIndex).SetInt(id)
+		}
+	}
+	return nil
+}
+
+// sliceElem returns the ith element of slice l. Pointer to element is returned for struct element,
+// so that generated values can be written back
+func sliceElem(l reflect.Value, i int) interface{} {
+	e := l.Index(i)
+	if e.Kind() == reflect.Struct && e.CanAddr() {
+		return e.Addr().Interface()
+	}
+	return e.Interface()
+}
+
+// InsertValues returns columns and values of records in the way Insert writes them, except that JSON values are text.
+// It's used to load records in other ways, e.g. pg.CopyFrom.
+// Like Insert, created_at and updated_at are stamped with current time, and zero version is set to 1.
+// Auto increment column is skipped if it's zero, and all records must have the same type and columns
+func InsertValues(records interface{}) ([]string, [][]interface{}, error) {
+	l := reflect.ValueOf(records)
+	if l.Kind() == reflect.Ptr {
+		l = l.Elem()
+	}
+	if l.Kind() != reflect.Slice {
+		return nil, nil, errors.New("not slice")
+	}
+
+	n := l.Len()
+	if n == 0 {
+		return nil, nil, nil
+	}
+
+	v := getStructValue(sliceElem(l, 0))
+	info := getColumnInfo(v.Type())
+	columns := insertColumns(v, info)
+	typ := v.Type()
+	aiZero := isAutoIncrementZero(v, info)
+	rows := make([][]interface{}, n)
+	// Time is provided by the default clock, as there's no table
+	t := new(Table)
+	for i := 0; i < n; i++ {
+		v = getStructValue(sliceElem(l, i))
+		if v.Type() != typ {
+			return nil, nil, fmt.Errorf("record %d: type %v is different from %v", i, v.Type(), typ)
+		}
+		if isAutoIncrementZero(v, info) != aiZero {
+			return nil, nil, fmt.Errorf("record %d: auto increment column %s must be all zero or all non-zero", i, info.aiName)
+		}
+		t.stampInsert(v, info)
+		if len(info.versionName) > 0 {
+			if f := v.FieldByIndex(info.nameToIndex[info.versionName]); f.Int() == 0 {
+				f.SetInt(1)
+			}
+		}
+		row := make([]interface{}, len(columns))
+		for j, name := range columns {
+			fv, err := getFieldValueByName(v, info, name)
+			if err != nil {
+				return nil, nil, fmt.Errorf("record %d: get %s: %w", i, name, err)
+			}
+			if b, ok := fv.([]byte); ok && IndexOfString(info.jsonNames, name) >= 0 {
+				fv = string(b)
+			}
+			row[j] = fv
+		}
+		rows[i] = row
+	}
+	return columns, rows, nil
+}
@@ -0,0 +1,99 @@
+package sql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// smallDialect limits number of bind variables to test chunking
+type smallDialect struct {
+	Dialect
+	noBatchID bool
+}
+
+func (d smallDialect) MaxParams() int {
+	return 4
+}
+
+func (d smallDialect) BatchInsertID(lastInsertID int64, i, n int) (int64, bool) {
+	if d.noBatchID {
+		return 0, false
+	}
+	return d.Dialect.BatchInsertID(lastInsertID, i, n)
+}
+
+func TestTable_BatchInsert(t *testing.T) {
+	type Product struct {
+		Tracked
+		ID      int64 `sql:"primary key,auto_increment"`
+		Name    string
+		Version int64 `sql:"version"`
+	}
+	db := openSQLite(t)
+	require.NoError(t, db.CreateTable(&Product{}))
+
+	t.Run("Chunks", func(t *testing.T) {
+		tbl := db.Table(&Product{})
+		tbl.dialect = smallDialect{Dialect: SQLite}
+		products := []*Product{{Name: "a"}, {Name: "b"}, {Name: "c"}, {Name: "d"}, {Name: "e"}}
+		require.NoError(t, tbl.BatchInsert(products))
+		for i, p := range products {
+			require.Equal(t, int64(i+1), p.ID)
+			require.Equal(t, int64(1), p.Version)
+		}
+
+		var l []*Product
+		require.NoError(t, db.Select(&l, "1=1 ORDER BY id"))
+		require.Len(t, l, 5)
+		require.Equal(t, "e", l[4].Name)
+		require.Equal(t, int64(1), l[4].Version)
+
+		// Records are tracked, so unchanged records are not updated
+		n, err := db.UpdateResult(products[0])
+		require.NoError(t, err)
+		require.Zero(t, n)
+	})
+
+	t.Run("NoBatchID", func(t *testing.T) {
+		tbl := db.Table(&Product{})
+		tbl.dialect = smallDialect{Dialect: SQLite, noBatchID: true}
+		products := []Product{{Name: "f"}, {Name: "g"}, {Name: "h"}}
+		require.NoError(t, tbl.BatchInsert(products))
+		for _, p := range products {
+			require.Zero(t, p.ID)
+			require.Equal(t, int64(1), p.Version)
+		}
+		n, err := db.Table(&Product{}).Where("name IN (?)", []string{"f", "g", "h"}).Count()
+		require.NoError(t, err)
+		require.Equal(t, 3, n)
+	})
+
+	t.Run("NoColumn", func(t *testing.T) {
+		type Counter struct {
+			ID int64 `sql:"primary key,auto_increment"`
+		}
+		require.Error(t, db.BatchInsert([]*Counter{{}, {}}))
+	})
+}
+
+func TestInsertValues_Stamp(t *testing.T) {
+	type Product struct {
+		ID        int64     `sql:"primary key,auto_increment"`
+		Version   int64     `sql:"version"`
+		CreatedAt int64     `sql:"created_at"`
+		UpdatedAt time.Time `sql:"updated_at"`
+	}
+	products := []*Product{{}, {CreatedAt: 100}}
+	columns, rows, err := InsertValues(products)
+	require.NoError(t, err)
+	require.Equal(t, []string{"version", "created_at", "updated_at"}, columns)
+	require.NotZero(t, products[0].CreatedAt)
+	require.Equal(t, int64(100), products[1].CreatedAt)
+	for i, p := range products {
+		require.Equal(t, int64(1), p.Version)
+		require.False(t, p.UpdatedAt.IsZero())
+		require.Equal(t, []interface{}{int64(1), p.CreatedAt, p.UpdatedAt}, rows[i])
+	}
+}
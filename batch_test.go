@@ -0,0 +1,26 @@
+package sql_test
+
+import (
+	"testing"
+
+	"github.com/gopub/sql"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInsertValues(t *testing.T) {
+	items := []*Item{
+		{ItemID: &ItemID{}, Name: "apple", Price: 0.1, Text: &Content{Title: "a"}},
+		{ItemID: &ItemID{}, Name: "banana", Price: 0.2, Email: "b@x.com"},
+	}
+	columns, rows, err := sql.InsertValues(items)
+	require.NoError(t, err)
+	require.Equal(t, []string{"name", "price", "txt", "email", "updated_at"}, columns)
+	require.Len(t, rows, 2)
+	require.Equal(t, `{"title":"a","desc":""}`, rows[0][2])
+	require.Nil(t, rows[0][3])
+	require.Equal(t, "b@x.com", rows[1][3])
+
+	items[1].ID = 2
+	_, _, err = sql.InsertValues(items)
+	require.Error(t, err)
+}
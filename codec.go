@@ -0,0 +1,163 @@
+package sql
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// EncodeFunc converts field value into a value which is supported by driver
+type EncodeFunc func(v interface{}) (driver.Value, error)
+
+// DecodeFunc converts column value src into the field which dst points to. src is never nil, as NULL sets the field to zero value
+type DecodeFunc func(src interface{}, dst interface{}) error
+
+// Codec converts values of types which can't implement sql.Scanner or driver.Valuer, e.g. types of third-party packages
+type Codec struct {
+	Encode EncodeFunc
+	Decode DecodeFunc
+}
+
+var (
+	_codecsMu    sync.RWMutex
+	_typeCodecs  = map[reflect.Type]*Codec{}
+	_namedCodecs = map[string]*Codec{}
+)
+
+// RegisterCodec makes typ a column type, which is converted by encode and decode. It's also used by pointer to typ.
+// Codecs must be registered before records are used, e.g. in init function
+func RegisterCodec(typ reflect.Type, encode EncodeFunc, decode DecodeFunc) {
+	if typ == nil {
+		panic("type is nil")
+	}
+	if typ.Kind() == reflect.Ptr {
+		panic("pointer type: " + typ.String())
+	}
+	c := newCodec(encode, decode)
+	_codecsMu.Lock()
+	_typeCodecs[typ] = c
+	_codecsMu.Unlock()
+}
+
+// RegisterNamedCodec registers codec which is selected by tag `sql:"codec=name"`
+func RegisterNamedCodec(name string, encode EncodeFunc, decode DecodeFunc) {
+	if !_regexpVariable.MatchString(name) {
+		panic("invalid codec name: " + name)
+	}
+	c := newCodec(encode, decode)
+	_codecsMu.Lock()
+	_namedCodecs[name] = c
+	_codecsMu.Unlock()
+}
+
+func newCodec(encode EncodeFunc, decode DecodeFunc) *Codec {
+	if encode == nil || decode == nil {
+		panic("encode or decode is nil")
+	}
+	return &Codec{Encode: encode, Decode: decode}
+}
+
+// typeCodec returns codec registered for typ or the type which typ points to, or nil if not found
+func typeCodec(typ reflect.Type) *Codec {
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	_codecsMu.RLock()
+	defer _codecsMu.RUnlock()
+	return _typeCodecs[typ]
+}
+
+func namedCodec(name string) *Codec {
+	_codecsMu.RLock()
+	defer _codecsMu.RUnlock()
+	return _namedCodecs[name]
+}
+
+// codecValue encodes v when it's used as a statement argument
+type codecValue struct {
+	v     interface{}
+	codec *Codec
+}
+
+var _ driver.Valuer = codecValue{}
+
+// Value encodes c.v, or the value which c.v points to. Nil pointer is NULL
+func (c codecValue) Value() (driver.Value, error) {
+	if v := reflect.ValueOf(c.v); v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, nil
+		}
+		return c.codec.Encode(v.Elem().Interface())
+	}
+	return c.codec.Encode(c.v)
+}
+
+func (c codecValue) String() string {
+	return fmt.Sprint(c.v)
+}
+
+// codecHolder scans column into field by codec
+type codecHolder struct {
+	v     reflect.Value
+	codec *Codec
+}
+
+var _ sql.Scanner = (*codecHolder)(nil)
+
+func (h *codecHolder) Scan(src interface{}) error {
+	if src == nil {
+		h.v.Set(reflect.Zero(h.v.Type()))
+		return nil
+	}
+
+	if h.v.Kind() == reflect.Ptr {
+		p := reflect.New(h.v.Type().Elem())
+		if err := h.codec.Decode(src, p.Interface()); err != nil {
+			return err
+		}
+		h.v.Set(p)
+		return nil
+	}
+	return h.codec.Decode(src, h.v.Addr().Interface())
+}
+
+// columnValue returns value of column as a statement argument without nullable check
+func columnValue(v reflect.Value, info *columnInfo, name string) interface{} {
+	field := v.FieldByIndex(info.nameToIndex[name])
+	if c := info.codecs[name]; c != nil {
+		return codecValue{v: field.Interface(), codec: c}
+	}
+	return field.Interface()
+}
+
+// encodeArgs returns args whose values of codec types are wrapped, and args is not changed
+func encodeArgs(args []interface{}) []interface{} {
+	_codecsMu.RLock()
+	n := len(_typeCodecs)
+	_codecsMu.RUnlock()
+	if n == 0 {
+		return args
+	}
+
+	var l []interface{}
+	for i, a := range args {
+		if a == nil {
+			continue
+		}
+		if _, ok := a.(driver.Valuer); ok {
+			continue
+		}
+		if c := typeCodec(reflect.TypeOf(a)); c != nil {
+			if l == nil {
+				l = append([]interface{}(nil), args...)
+			}
+			l[i] = codecValue{v: a, codec: c}
+		}
+	}
+	if l == nil {
+		return args
+	}
+	return l
+}
@@ -0,0 +1,82 @@
+package sql
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type codecPoint struct {
+	X, Y int
+}
+
+func init() {
+	RegisterCodec(reflect.TypeOf(codecPoint{}), func(v interface{}) (driver.Value, error) {
+		p := v.(codecPoint)
+		return fmt.Sprintf("%d,%d", p.X, p.Y), nil
+	}, func(src interface{}, dst interface{}) error {
+		s, ok := src.(string)
+		if !ok {
+			return errors.New("not string")
+		}
+		p := dst.(*codecPoint)
+		_, err := fmt.Sscanf(s, "%d,%d", &p.X, &p.Y)
+		return err
+	})
+
+	RegisterNamedCodec("csv", func(v interface{}) (driver.Value, error) {
+		return strings.Join(v.([]string), ","), nil
+	}, func(src interface{}, dst interface{}) error {
+		*dst.(*[]string) = strings.Split(src.(string), ",")
+		return nil
+	})
+}
+
+func TestCodec(t *testing.T) {
+	type Place struct {
+		ID     int `sql:"primary key"`
+		Center codecPoint
+		Corner *codecPoint
+		Tags   []string `sql:"codec=csv"`
+	}
+	info := parseColumnInfo(reflect.TypeOf(Place{}))
+	require.Equal(t, []string{"id", "center", "corner", "tags"}, info.names)
+	require.Len(t, info.codecs, 3)
+
+	p := reflect.ValueOf(&Place{Center: codecPoint{1, 2}, Tags: []string{"a", "b"}}).Elem()
+	v, err := getFieldValueByName(p, info, "center")
+	require.NoError(t, err)
+	require.Equal(t, "1,2", v)
+	v, err = getFieldValueByName(p, info, "corner")
+	require.NoError(t, err)
+	require.Nil(t, v)
+	v, err = getFieldValueByName(p, info, "tags")
+	require.NoError(t, err)
+	require.Equal(t, "a,b", v)
+
+	p = newRecord(reflect.TypeOf(Place{})).Elem()
+	require.NoError(t, scanDest(p, info, "center").(*codecHolder).Scan("3,4"))
+	require.NoError(t, scanDest(p, info, "corner").(*codecHolder).Scan("5,6"))
+	require.NoError(t, scanDest(p, info, "tags").(*codecHolder).Scan("x,y"))
+	require.Equal(t, Place{Center: codecPoint{3, 4}, Corner: &codecPoint{5, 6}, Tags: []string{"x", "y"}}, p.Interface())
+
+	args := []interface{}{codecPoint{7, 8}, 1}
+	encoded := encodeArgs(args)
+	require.Equal(t, codecPoint{7, 8}, args[0])
+	v, err = encoded[0].(driver.Valuer).Value()
+	require.NoError(t, err)
+	require.Equal(t, "7,8", v)
+
+	type Invalid struct {
+		ID   int      `sql:"primary key"`
+		Tags []string `sql:"codec=unknown"`
+	}
+	require.Panics(t, func() {
+		parseColumnInfo(reflect.TypeOf(Invalid{}))
+	})
+}
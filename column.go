@@ -1,10 +1,12 @@
 package sql
 
 import (
+	"fmt"
 	"reflect"
 	"regexp"
-	"strings"
+	"strconv"
 	"sync"
+	"time"
 	"unsafe"
 
 	"github.com/gopub/conv"
@@ -13,27 +15,9 @@ import (
 var _regexpVariable = regexp.MustCompile("^[_a-zA-Z][_a-zA-Z0-9]*$")
 var _bytesType = reflect.TypeOf([]byte(nil))
 var _int64Type = reflect.TypeOf(int64(0))
-var _typeToColumnInfo = &sync.Map{} //type:*columnInfo
-var _sqlKeywords = map[string]struct{}{
-	"primary":        {},
-	"key":            {},
-	"auto_increment": {},
-	"insert":         {},
-	"create":         {},
-	"table":          {},
-	"database":       {},
-	"select":         {},
-	"update":         {},
-	"unique":         {},
-	"int":            {},
-	"bigint":         {},
-	"bool":           {},
-	"tinyint":        {},
-	"double":         {},
-	"date":           {},
-	"json":           {},
-	"nullable":       {},
-}
+var _timeType = reflect.TypeOf(time.Time{})
+var _typeToColumnInfo = &sync.Map{}      //type:*columnInfo
+var _tableToSoftDeleteType = &sync.Map{} //table name:reflect.Type
 
 type fieldIndex []int
 
@@ -66,6 +50,26 @@ type columnInfo struct {
 
 	nullableNames []string
 
+	//codecs of columns whose types or tags have codecs
+	codecs map[string]*Codec
+
+	//optimistic locking version column name
+	versionName string
+
+	//soft delete column name
+	deletedName string
+
+	//timestamp column names, which are time.Time or unix time
+	createdName string
+	updatedName string
+
+	//DDL options
+	types       map[string]string
+	sizes       map[string]int
+	defaults    map[string]string
+	uniqueNames []string
+	indexNames  []string
+
 	//for speed
 	notPKNames []string
 	notAINames []string
@@ -82,9 +86,25 @@ func getColumnInfo(typ reflect.Type) *columnInfo {
 
 	info := parseColumnInfo(typ)
 	_typeToColumnInfo.Store(typ, info)
+	if len(info.deletedName) > 0 {
+		// Table created by name finds soft delete column by record type
+		_tableToSoftDeleteType.LoadOrStore(getTableNameByType(typ), typ)
+	}
 	return info
 }
 
+// Register parses record types, so that tables created by name know their soft delete columns
+// before records of these types are used
+func Register(records ...interface{}) {
+	for _, r := range records {
+		typ := reflect.TypeOf(r)
+		for typ.Kind() == reflect.Ptr {
+			typ = typ.Elem()
+		}
+		getColumnInfo(typ)
+	}
+}
+
 func parseColumnInfo(typ reflect.Type) *columnInfo {
 	for typ.Kind() == reflect.Ptr {
 		typ = typ.Elem()
@@ -100,41 +120,45 @@ func parseColumnInfo(typ reflect.Type) *columnInfo {
 	fields := getAllFields(typ)
 
 	for _, f := range fields {
-		tag := strings.TrimSpace(strings.ToLower(f.Tag.Get("sql")))
-		if tag == "-" {
+		tag := f.tag
+		if tag.ignore {
 			continue
 		}
 
 		if f.Name[0] < 'A' || f.Name[0] > 'Z' {
-			if len(tag) > 0 {
+			if !tag.empty() {
 				panic("sql column must be exported field: " + f.Name)
 			}
 			continue
 		}
 
-		isJSON := strings.Contains(tag, "json")
-		nullable := strings.Contains(tag, "nullable")
+		isJSON := tag.has(tagJSON)
+		nullable := tag.has(tagNullable)
 
-		if !isJSON && !isSupportType(f.Type) {
-			if len(tag) > 0 {
-				panic("invalid type: db column " + typ.Name() + ":" + f.Type.String())
+		var codec *Codec
+		if codecName, ok := tag.value(tagCodec); ok {
+			if isJSON {
+				panic("json column can't have codec: " + typ.Name() + ":" + f.Name)
 			}
-			continue
+			if codec = namedCodec(codecName); codec == nil {
+				panic("codec not found: " + codecName)
+			}
+		} else if !isJSON {
+			codec = typeCodec(f.Type)
 		}
 
-		var name string
-		if len(tag) > 0 {
-			strs := strings.Split(tag, ",")
-			if len(strs) > 0 {
-				if _, ok := _sqlKeywords[strs[0]]; !ok && _regexpVariable.MatchString(strs[0]) {
-					name = strs[0]
-				}
+		if !isJSON && codec == nil && !isSupportType(f.Type) {
+			if !tag.empty() {
+				panic("invalid type: db column " + typ.Name() + ":" + f.Type.String())
 			}
+			continue
 		}
 
+		name := tag.name
 		if len(name) == 0 {
 			name = conv.ToSnake(f.Name)
 		}
+		name = f.prefix + name
 
 		if idx, found := info.nameToIndex[name]; found {
 			if len(idx) < len(f.Index) {
@@ -146,14 +170,19 @@ func parseColumnInfo(typ reflect.Type) *columnInfo {
 			}
 		}
 
-		if strings.Contains(tag, "primary key") {
+		// Pointer is nullable naturally
+		if f.Type.Kind() == reflect.Ptr && !isJSON {
+			nullable = true
+		}
+
+		if tag.has(tagPrimaryKey) {
 			if isJSON {
 				panic("json column can't be primary key")
 			}
 			info.pkNames = append(info.pkNames, name)
 		}
 
-		if strings.Contains(tag, "auto_increment") {
+		if tag.has(tagAutoIncrement) {
 			if len(info.aiName) > 0 {
 				panic("duplicate auto_increment")
 			}
@@ -164,6 +193,85 @@ func parseColumnInfo(typ reflect.Type) *columnInfo {
 			info.aiName = name
 		}
 
+		if tag.has(tagVersion) {
+			if len(info.versionName) > 0 {
+				panic("duplicate version")
+			}
+
+			if !isIntKind(f.Type.Kind()) {
+				panic("not integer: " + f.Type.String())
+			}
+			info.versionName = name
+		}
+
+		if tag.has(tagDeletedAt) {
+			if len(info.deletedName) > 0 {
+				panic("duplicate deleted_at")
+			}
+
+			if t := f.Type; t != _timeType && !isIntKind(t.Kind()) && (t.Kind() != reflect.Ptr || t.Elem() != _timeType) {
+				panic("deleted_at must be time.Time, *time.Time or unix time: " + f.Type.String())
+			}
+			info.deletedName = name
+			// Rows are not deleted if deleted_at is NULL
+			nullable = true
+		}
+
+		if tag.has(tagCreatedAt) {
+			if len(info.createdName) > 0 {
+				panic("duplicate created_at")
+			}
+
+			if f.Type != _timeType && !isIntKind(f.Type.Kind()) {
+				panic("created_at must be time.Time or unix time: " + f.Type.String())
+			}
+			info.createdName = name
+		}
+
+		if tag.has(tagUpdatedAt) {
+			if len(info.updatedName) > 0 {
+				panic("duplicate updated_at")
+			}
+
+			if f.Type != _timeType && !isIntKind(f.Type.Kind()) {
+				panic("updated_at must be time.Time or unix time: " + f.Type.String())
+			}
+			info.updatedName = name
+		}
+
+		if t, ok := tag.value(tagType); ok {
+			if info.types == nil {
+				info.types = make(map[string]string)
+			}
+			info.types[name] = t
+		}
+
+		if size, ok := tag.value(tagSize); ok {
+			n, err := strconv.Atoi(size)
+			if err != nil || n <= 0 {
+				panic("invalid size: " + typ.Name() + ":" + f.Name)
+			}
+			if info.sizes == nil {
+				info.sizes = make(map[string]int)
+			}
+			info.sizes[name] = n
+		}
+
+		if def, ok := tag.value(tagDefault); ok {
+			if info.defaults == nil {
+				info.defaults = make(map[string]string)
+			}
+			info.defaults[name] = def
+		}
+
+		if tag.has(tagUnique) {
+			info.uniqueNames = append(info.uniqueNames, name)
+		}
+
+		if tag.has(tagIndex) {
+			info.indexNames = append(info.indexNames, name)
+		}
+
 		info.indexes = append(info.indexes, f.Index)
 		info.names = append(info.names, name)
 		info.nameToIndex[name] = f.Index
@@ -171,6 +279,13 @@ func parseColumnInfo(typ reflect.Type) *columnInfo {
 			info.jsonNames = append(info.jsonNames, name)
 		}
 
+		if codec != nil {
+			if info.codecs == nil {
+				info.codecs = make(map[string]*Codec)
+			}
+			info.codecs[name] = codec
+		}
+
 		if nullable {
 			info.nullableNames = append(info.nullableNames, name)
 		}
@@ -202,11 +317,33 @@ func parseColumnInfo(typ reflect.Type) *columnInfo {
 	return info
 }
 
+func isIntKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	default:
+		return false
+	}
+}
+
+// isSupportType returns true if typ can be a column: scalar types, []byte, time.Time,
+// types implementing sql.Scanner or driver.Valuer, types with codecs, and pointers to them
 func isSupportType(typ reflect.Type) bool {
 	if typ == nil {
 		return false
 	}
 
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+		if typ.Kind() == reflect.Ptr {
+			return false
+		}
+	}
+
+	if typ == _timeType || isScannerOrValuer(typ) || typeCodec(typ) != nil {
+		return true
+	}
+
 	switch typ.Kind() {
 	case reflect.Bool, reflect.Float32, reflect.Float64, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
 		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.String:
@@ -220,24 +357,55 @@ func isSupportType(typ reflect.Type) bool {
 	return false
 }
 
-func getAllFields(typ reflect.Type) []reflect.StructField {
-	fields := make([]reflect.StructField, 0)
+func isScannerOrValuer(typ reflect.Type) bool {
+	p := reflect.PtrTo(typ)
+	return p.Implements(_scannerType) || typ.Implements(_valuerType) || p.Implements(_valuerType)
+}
+
+// structField is a field of struct or its embedded structs
+type structField struct {
+	reflect.StructField
+	tag *columnTag
+
+	//prefix of column name
+	prefix string
+}
+
+func getAllFields(typ reflect.Type) []*structField {
+	return appendFields(nil, typ, nil, "")
+}
+
+// appendFields appends fields of typ. Fields of embedded struct, or struct with prefix option are flattened
+func appendFields(fields []*structField, typ reflect.Type, index []int, prefix string) []*structField {
 	for i := 0; i < typ.NumField(); i++ {
 		f := typ.Field(i)
-		if f.Anonymous {
-			t := f.Type
-			if t.Kind() == reflect.Ptr {
-				t = t.Elem()
+		f.Index = append(append(make([]int, 0, len(index)+1), index...), i)
+		tag, err := parseTag(f.Tag.Get("sql"))
+		if err != nil {
+			panic(fmt.Sprintf("invalid sql tag of %s.%s: %v", typ.Name(), f.Name, err))
+		}
+
+		t := f.Type
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		p, hasPrefix := tag.value(tagPrefix)
+		_, hasCodec := tag.value(tagCodec)
+		if t.Kind() == reflect.Struct && !isSupportType(t) && !tag.has(tagJSON) && !hasCodec && (f.Anonymous || hasPrefix) {
+			if tag.ignore {
+				continue
 			}
-			subFields := getAllFields(t)
-			for i := range subFields {
-				subFields[i].Index = append([]int{i}, subFields[i].Index...)
+			if len(tag.name) > 0 || len(tag.flags) > 0 || len(tag.values) > 1 || (len(tag.values) == 1 && !hasPrefix) {
+				panic(fmt.Sprintf("invalid sql tag of %s.%s: only prefix is allowed for struct", typ.Name(), f.Name))
 			}
-			fields = append(fields, subFields...)
-		} else {
-			fields = append(fields, f)
+			fields = appendFields(fields, t, f.Index, prefix+p)
+			continue
 		}
-	}
 
+		if hasPrefix {
+			panic(fmt.Sprintf("invalid sql tag of %s.%s: prefix is only allowed for struct", typ.Name(), f.Name))
+		}
+		fields = append(fields, &structField{StructField: f, tag: tag, prefix: prefix})
+	}
 	return fields
 }
@@ -0,0 +1,91 @@
+package sql
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseColumnInfo(t *testing.T) {
+	t.Run("Version", func(t *testing.T) {
+		type Product struct {
+			ID      int `sql:"primary key,auto_increment"`
+			Name    string
+			Version int64 `sql:"version"`
+		}
+		info := parseColumnInfo(reflect.TypeOf(Product{}))
+		require.Equal(t, "version", info.versionName)
+		require.Equal(t, []string{"name", "version"}, info.notPKNames)
+	})
+
+	t.Run("InvalidVersion", func(t *testing.T) {
+		type Product struct {
+			ID      int    `sql:"primary key"`
+			Version string `sql:"version"`
+		}
+		require.Panics(t, func() {
+			parseColumnInfo(reflect.TypeOf(Product{}))
+		})
+	})
+
+	t.Run("Timestamp", func(t *testing.T) {
+		type Product struct {
+			ID        int       `sql:"primary key"`
+			CreatedAt time.Time `sql:"created_at"`
+			UpdatedAt int64     `sql:"updated_at"`
+		}
+		info := parseColumnInfo(reflect.TypeOf(Product{}))
+		require.Equal(t, "created_at", info.createdName)
+		require.Equal(t, "updated_at", info.updatedName)
+
+		type Invalid struct {
+			ID        int    `sql:"primary key"`
+			UpdatedAt string `sql:"updated_at"`
+		}
+		require.Panics(t, func() {
+			parseColumnInfo(reflect.TypeOf(Invalid{}))
+		})
+	})
+
+	t.Run("Embedded", func(t *testing.T) {
+		type ID struct {
+			ID int `sql:"primary key"`
+		}
+		type Product struct {
+			Tracked
+			Name string
+			ID
+		}
+		info := parseColumnInfo(reflect.TypeOf(Product{}))
+		require.Equal(t, []string{"name", "id"}, info.names)
+		require.Equal(t, fieldIndex{2, 0}, info.nameToIndex["id"])
+	})
+
+	t.Run("Types", func(t *testing.T) {
+		type Wallet struct {
+			ID        int `sql:"primary key"`
+			Note      *string
+			Balance   BigInt
+			Limit     *BigInt
+			CreatedAt time.Time
+			ClosedAt  *time.Time
+		}
+		info := parseColumnInfo(reflect.TypeOf(Wallet{}))
+		require.Equal(t, []string{"id", "note", "balance", "limit", "created_at", "closed_at"}, info.names)
+		require.Equal(t, []string{"note", "limit", "closed_at"}, info.nullableNames)
+
+		w := reflect.ValueOf(&Wallet{ID: 1}).Elem()
+		v, err := getFieldValueByName(w, info, "note")
+		require.NoError(t, err)
+		require.Nil(t, v)
+		v, err = getFieldValueByName(w, info, "balance")
+		require.NoError(t, err)
+		require.IsType(t, &BigInt{}, v)
+
+		w = newRecord(reflect.TypeOf(Wallet{})).Elem()
+		require.IsType(t, new(*string), scanDest(w, info, "note"))
+		require.IsType(t, new(time.Time), scanDest(w, info, "created_at"))
+	})
+}
@@ -0,0 +1,386 @@
+package sql
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Condition is a boolean expression used in where clause
+type Condition interface {
+	// ToSQL returns expression with ? placeholders and arguments
+	ToSQL() (string, []interface{})
+}
+
+type expr struct {
+	query string
+	args  []interface{}
+}
+
+// Expr returns condition of raw expression. Slice argument is expanded into a list of placeholders
+// e.g. Expr("id IN (?)", []int{1, 2}) is rendered to "id IN (?, ?)"
+func Expr(query string, args ...interface{}) Condition {
+	return &expr{query: query, args: args}
+}
+
+func (e *expr) ToSQL() (string, []interface{}) {
+	return expandArgs(e.query, e.args)
+}
+
+type compare struct {
+	column string
+	op     string
+	value  interface{}
+}
+
+func (c *compare) ToSQL() (string, []interface{}) {
+	return c.column + " " + c.op + " ?", []interface{}{c.value}
+}
+
+// Eq returns condition column = value. Nil value is rendered to column IS NULL
+func Eq(column string, value interface{}) Condition {
+	if value == nil {
+		return IsNull(column)
+	}
+	return &compare{column: column, op: "=", value: value}
+}
+
+// Ne returns condition column <> value. Nil value is rendered to column IS NOT NULL
+func Ne(column string, value interface{}) Condition {
+	if value == nil {
+		return IsNotNull(column)
+	}
+	return &compare{column: column, op: "<>", value: value}
+}
+
+func Lt(column string, value interface{}) Condition {
+	return &compare{column: column, op: "<", value: value}
+}
+
+func Lte(column string, value interface{}) Condition {
+	return &compare{column: column, op: "<=", value: value}
+}
+
+func Gt(column string, value interface{}) Condition {
+	return &compare{column: column, op: ">", value: value}
+}
+
+func Gte(column string, value interface{}) Condition {
+	return &compare{column: column, op: ">=", value: value}
+}
+
+func Like(column string, pattern string) Condition {
+	return &compare{column: column, op: "LIKE", value: pattern}
+}
+
+type between struct {
+	column string
+	low    interface{}
+	high   interface{}
+}
+
+func (b *between) ToSQL() (string, []interface{}) {
+	return b.column + " BETWEEN ? AND ?", []interface{}{b.low, b.high}
+}
+
+func Between(column string, low, high interface{}) Condition {
+	return &between{column: column, low: low, high: high}
+}
+
+type isNull struct {
+	column string
+	not    bool
+}
+
+func (n *isNull) ToSQL() (string, []interface{}) {
+	if n.not {
+		return n.column + " IS NOT NULL", nil
+	}
+	return n.column + " IS NULL", nil
+}
+
+func IsNull(column string) Condition {
+	return &isNull{column: column}
+}
+
+func IsNotNull(column string) Condition {
+	return &isNull{column: column, not: true}
+}
+
+type in struct {
+	column string
+	values []interface{}
+	not    bool
+}
+
+func (c *in) ToSQL() (string, []interface{}) {
+	if len(c.values) == 0 {
+		// Nothing is IN an empty list, and everything is NOT IN it
+		if c.not {
+			return "1 = 1", nil
+		}
+		return "1 = 0", nil
+	}
+	op := " IN ("
+	if c.not {
+		op = " NOT IN ("
+	}
+	return c.column + op + placeholders(len(c.values)) + ")", c.values
+}
+
+// In returns condition column IN (values...). values must be a slice or an array
+// Empty values is rendered to a false condition
+func In(column string, values interface{}) Condition {
+	return &in{column: column, values: toInterfaceSlice(values)}
+}
+
+// NotIn returns condition column NOT IN (values...). values must be a slice or an array
+// Empty values is rendered to a true condition
+func NotIn(column string, values interface{}) Condition {
+	return &in{column: column, values: toInterfaceSlice(values), not: true}
+}
+
+type junction struct {
+	op    string
+	conds []Condition
+}
+
+func (j *junction) ToSQL() (string, []interface{}) {
+	var b strings.Builder
+	var args []interface{}
+	n := 0
+	for _, c := range j.conds {
+		if c == nil {
+			continue
+		}
+		s, a := c.ToSQL()
+		if n > 0 {
+			b.WriteString(" ")
+			b.WriteString(j.op)
+			b.WriteString(" ")
+		}
+		b.WriteString("(")
+		b.WriteString(s)
+		b.WriteString(")")
+		args = append(args, a...)
+		n++
+	}
+	if n == 0 {
+		// Identity element: AND of nothing is true, OR of nothing is false
+		if j.op == "AND" {
+			return "1 = 1", nil
+		}
+		return "1 = 0", nil
+	}
+	return b.String(), args
+}
+
+// And joins conditions by AND. Nil conditions are skipped
+func And(conds ...Condition) Condition {
+	return &junction{op: "AND", conds: conds}
+}
+
+// Or joins conditions by OR. Nil conditions are skipped
+func Or(conds ...Condition) Condition {
+	return &junction{op: "OR", conds: conds}
+}
+
+type not struct {
+	cond Condition
+}
+
+func (n *not) ToSQL() (string, []interface{}) {
+	s, args := n.cond.ToSQL()
+	return "NOT (" + s + ")", args
+}
+
+func Not(cond Condition) Condition {
+	return &not{cond: cond}
+}
+
+// toCondition converts where clause into condition. where can be a string with args, or a Condition
+func toCondition(where interface{}, args []interface{}) Condition {
+	switch w := where.(type) {
+	case nil:
+		return nil
+	case string:
+		if len(w) == 0 {
+			return nil
+		}
+		return Expr(w, args...)
+	case Condition:
+		if len(args) > 0 {
+			panic("args must be empty if where is a Condition")
+		}
+		return w
+	default:
+		panic(fmt.Sprintf("invalid where type: %T", where))
+	}
+}
+
+func placeholders(n int) string {
+	if n <= 0 {
+		return ""
+	}
+	return strings.Repeat("?, ", n-1) + "?"
+}
+
+var _valuerType = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+
+// isListArg returns true if arg is a slice or an array which should be expanded into a list of placeholders
+func isListArg(arg interface{}) bool {
+	if arg == nil {
+		return false
+	}
+	t := reflect.TypeOf(arg)
+	if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+		return false
+	}
+	if t.Elem().Kind() == reflect.Uint8 || t.Implements(_valuerType) {
+		return false
+	}
+	return true
+}
+
+// emptyInCondition splits s which ends with "x IN (" or "x NOT IN (" into the part before x,
+// and the condition of an empty list
+func emptyInCondition(s string) (string, string, bool) {
+	s = strings.TrimRight(s, " \t\r\n")
+	if !strings.HasSuffix(s, "(") {
+		return "", "", false
+	}
+	s = strings.TrimRight(s[:len(s)-1], " \t\r\n")
+	if len(s) < 3 || !strings.EqualFold(s[len(s)-2:], "IN") || !isSpace(s[len(s)-3]) {
+		return "", "", false
+	}
+	s = strings.TrimRight(s[:len(s)-2], " \t\r\n")
+	cond := "1 = 0"
+	if len(s) >= 4 && strings.EqualFold(s[len(s)-3:], "NOT") && isSpace(s[len(s)-4]) {
+		cond = "1 = 1"
+		s = strings.TrimRight(s[:len(s)-3], " \t\r\n")
+	}
+
+	// Operand is an identifier which may be qualified or quoted, or a function call or a row value
+	i := len(s)
+	for i > 0 {
+		c := s[i-1]
+		switch {
+		case c == ')':
+			depth := 0
+			for i > 0 {
+				i--
+				if s[i] == ')' {
+					depth++
+				} else if s[i] == '(' {
+					depth--
+					if depth == 0 {
+						break
+					}
+				}
+			}
+			if depth != 0 {
+				return "", "", false
+			}
+		case c == '"' || c == '`':
+			k := strings.LastIndexByte(s[:i-1], c)
+			if k < 0 {
+				return "", "", false
+			}
+			i = k
+		case c == '_' || c == '.' || c == '$' || isAlphaNum(c):
+			i--
+		default:
+			if i == len(s) {
+				return "", "", false
+			}
+			return s[:i], cond, true
+		}
+	}
+	if i == len(s) {
+		return "", "", false
+	}
+	return s[:i], cond, true
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n'
+}
+
+func isAlphaNum(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+func toInterfaceSlice(values interface{}) []interface{} {
+	if values == nil {
+		return nil
+	}
+	if l, ok := values.([]interface{}); ok {
+		return l
+	}
+	v := reflect.ValueOf(values)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		panic(fmt.Sprintf("not slice or array: %T", values))
+	}
+	l := make([]interface{}, v.Len())
+	for i := range l {
+		l[i] = v.Index(i).Interface()
+	}
+	return l
+}
+
+// expandArgs expands slice arguments into lists of placeholders.
+// Like In and NotIn, "x IN (?)" of empty slice is rendered to 1 = 0, and "x NOT IN (?)" to 1 = 1.
+// It panics if empty slice is used in other expressions
+func expandArgs(query string, args []interface{}) (string, []interface{}) {
+	expand := false
+	for _, a := range args {
+		if isListArg(a) {
+			expand = true
+			break
+		}
+	}
+	if !expand {
+		return query, args
+	}
+
+	var b strings.Builder
+	expanded := make([]interface{}, 0, len(args))
+	i := 0
+	var quote byte
+	for j := 0; j < len(query); j++ {
+		c := query[j]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '?' && i < len(args):
+			a := args[i]
+			i++
+			if isListArg(a) {
+				l := toInterfaceSlice(a)
+				if len(l) > 0 {
+					b.WriteString(placeholders(len(l)))
+					expanded = append(expanded, l...)
+					continue
+				}
+				prefix, cond, ok := emptyInCondition(b.String())
+				end := strings.IndexByte(query[j+1:], ')')
+				if !ok || end < 0 || len(strings.TrimSpace(query[j+1:j+1+end])) > 0 {
+					panic("empty slice must be used in IN (?) or NOT IN (?): " + query)
+				}
+				b.Reset()
+				b.WriteString(prefix)
+				b.WriteString(cond)
+				j += end + 1
+				continue
+			}
+			expanded = append(expanded, a)
+		}
+		b.WriteByte(c)
+	}
+	return b.String(), append(expanded, args[i:]...)
+}
@@ -0,0 +1,56 @@
+package sql_test
+
+import (
+	"testing"
+
+	"github.com/gopub/sql"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCondition(t *testing.T) {
+	tests := []struct {
+		cond  sql.Condition
+		query string
+		args  []interface{}
+	}{
+		{sql.Eq("id", 1), "id = ?", []interface{}{1}},
+		{sql.Eq("email", nil), "email IS NULL", nil},
+		{sql.Ne("id", 1), "id <> ?", []interface{}{1}},
+		{sql.Lt("price", 0.2), "price < ?", []interface{}{0.2}},
+		{sql.Gte("price", 0.2), "price >= ?", []interface{}{0.2}},
+		{sql.Like("name", "a%"), "name LIKE ?", []interface{}{"a%"}},
+		{sql.Between("price", 1, 2), "price BETWEEN ? AND ?", []interface{}{1, 2}},
+		{sql.IsNull("email"), "email IS NULL", nil},
+		{sql.IsNotNull("email"), "email IS NOT NULL", nil},
+		{sql.In("id", []int64{1, 2, 3}), "id IN (?, ?, ?)", []interface{}{int64(1), int64(2), int64(3)}},
+		{sql.In("id", []int64{}), "1 = 0", nil},
+		{sql.NotIn("id", []string{"a"}), "id NOT IN (?)", []interface{}{"a"}},
+		{sql.NotIn("id", nil), "1 = 1", nil},
+		{sql.And(sql.Eq("a", 1), sql.Or(sql.Eq("b", 2), sql.Eq("c", 3))), "(a = ?) AND ((b = ?) OR (c = ?))", []interface{}{1, 2, 3}},
+		{sql.Or(), "1 = 0", nil},
+		{sql.Not(sql.In("id", []int{1})), "NOT (id IN (?))", []interface{}{1}},
+		{sql.Expr("id IN (?) AND name = ?", []int{1, 2}, "a"), "id IN (?, ?) AND name = ?", []interface{}{1, 2, "a"}},
+		{sql.Expr("id IN (?)", []int{}), "1 = 0", nil},
+		{sql.Expr("a = ? AND p.id not in ( ? ) AND b = ?", 1, []int{}, 2), "a = ? AND 1 = 1 AND b = ?", []interface{}{1, 2}},
+		{sql.Expr("LOWER(`name`) IN (?) OR (a, b) NOT IN (?)", []string{}, [][]int{}), "1 = 0 OR 1 = 1", nil},
+		{sql.Expr("data = ?", []byte("abc")), "data = ?", []interface{}{[]byte("abc")}},
+	}
+
+	for _, test := range tests {
+		query, args := test.cond.ToSQL()
+		require.Equal(t, test.query, query)
+		if len(test.args) == 0 {
+			require.Empty(t, args)
+		} else {
+			require.Equal(t, test.args, args)
+		}
+	}
+}
+
+func TestExpr_EmptySlice(t *testing.T) {
+	for _, query := range []string{"id = ?", "id IN ? ", "id IN (?, 1)", "IN (?)"} {
+		require.Panics(t, func() {
+			sql.Expr(query, []int{}).ToSQL()
+		}, query)
+	}
+}
@@ -1,31 +1,39 @@
 package sql
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"reflect"
-
-	"github.com/gopub/log"
 )
 
 var _tableNamingType = reflect.TypeOf((*tableNaming)(nil)).Elem()
 
 type DBWrapper struct {
-	db         *sql.DB
-	driverName string
+	session
+	db *sql.DB
 }
 
 // NewDBWrapper opens database
 // dataSourceName's format: username:password@tcp(host:port)/dbName
+// Dialect is selected by driverName, and Generic is used if no dialect is registered for driverName, see RegisterDialect
 func NewDBWrapper(driverName, dataSourceName string) (*DBWrapper, error) {
+	dialect := GetDialect(driverName)
+	if dialect == nil {
+		dialect = Generic
+	}
+
 	db, err := sql.Open(driverName, dataSourceName)
 	if err != nil {
 		return nil, err
 	}
 
 	return &DBWrapper{
-		db:         db,
-		driverName: driverName,
+		session: session{
+			exe:     db,
+			dialect: dialect,
+		},
+		db: db,
 	}, nil
 }
 
@@ -33,11 +41,6 @@ func (d *DBWrapper) DB() *sql.DB {
 	return d.db
 }
 
-func (d *DBWrapper) Exec(query string, args ...interface{}) (sql.Result, error) {
-	log.Debug(query, args)
-	return d.db.Exec(query, args...)
-}
-
 func (d *DBWrapper) MustExec(query string, args ...interface{}) {
 	_, err := d.db.Exec(query, args...)
 	if err != nil {
@@ -45,15 +48,37 @@ func (d *DBWrapper) MustExec(query string, args ...interface{}) {
 	}
 }
 
+// SetClock sets clock which provides time of created_at, updated_at and deleted_at columns.
+// Transactions begun later share the clock
+func (d *DBWrapper) SetClock(c Clock) {
+	d.clock = c
+}
+
+// SetErrNotFound makes Update and Delete of a record return ErrNotFound if no row matches its primary key.
+// MySQL reports unchanged rows as not affected, so clientFoundRows=true is required in dataSourceName.
+// Transactions begun later share the setting
+func (d *DBWrapper) SetErrNotFound(enabled bool) {
+	d.errNotFound = enabled
+}
+
 func (d *DBWrapper) Begin() (*TxWrapper, error) {
-	tx, err := d.db.Begin()
+	return d.BeginTx(context.Background(), nil)
+}
+
+func (d *DBWrapper) BeginTx(ctx context.Context, opts *TxOptions) (*TxWrapper, error) {
+	tx, err := d.db.BeginTx(ctx, opts)
 	if err != nil {
 		return nil, err
 	}
 
 	return &TxWrapper{
-		tx:         tx,
-		driverName: d.driverName,
+		session: session{
+			exe:         tx,
+			dialect:     d.dialect,
+			clock:       d.clock,
+			errNotFound: d.errNotFound,
+		},
+		tx: tx,
 	}, nil
 }
 
@@ -61,53 +86,40 @@ func (d *DBWrapper) Close() error {
 	return d.db.Close()
 }
 
-func (d *DBWrapper) Table(nameOrRecord interface{}) *Table {
-	name, ok := nameOrRecord.(string)
-	if !ok {
-		name = getTableName(nameOrRecord)
-	}
-
-	return &Table{
-		exe:        d.db,
-		driverName: d.driverName,
-		name:       name,
-	}
-}
-
-func (d *DBWrapper) Insert(record interface{}) error {
-	return d.Table(getTableName(record)).Insert(record)
+// BatchInsert inserts records with multi-row insert statements in a transaction
+func (d *DBWrapper) BatchInsert(values interface{}) error {
+	return d.BatchInsertContext(context.Background(), values)
 }
 
-func (d *DBWrapper) BatchInsert(values interface{}) error {
-	l := reflect.ValueOf(values)
-	if l.Kind() != reflect.Slice {
-		return errors.New("not slice")
+func (d *DBWrapper) BatchInsertContext(ctx context.Context, values interface{}) error {
+	tx, err := d.BeginTx(ctx, nil)
+	if err != nil {
+		return err
 	}
-
-	tx, err := d.Begin()
-	for i := 0; i < l.Len(); i++ {
-		err = tx.Insert(l.Index(i).Interface())
-		if err != nil {
-			tx.Rollback()
-			return err
-		}
+	err = tx.BatchInsertContext(ctx, values)
+	if err != nil {
+		tx.Rollback()
+		return err
 	}
 	return tx.Commit()
 }
 
-func (d *DBWrapper) Update(record interface{}) error {
-	return d.Table(getTableName(record)).Update(record)
+func (d *DBWrapper) BatchUpdate(values interface{}) error {
+	return d.BatchUpdateContext(context.Background(), values)
 }
 
-func (d *DBWrapper) BatchUpdate(values interface{}) error {
+func (d *DBWrapper) BatchUpdateContext(ctx context.Context, values interface{}) error {
 	l := reflect.ValueOf(values)
 	if l.Kind() != reflect.Slice {
 		return errors.New("not slice")
 	}
 
-	tx, err := d.Begin()
+	tx, err := d.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
 	for i := 0; i < l.Len(); i++ {
-		err = tx.Update(l.Index(i).Interface())
+		err = tx.UpdateContext(ctx, l.Index(i).Interface())
 		if err != nil {
 			tx.Rollback()
 			return err
@@ -116,19 +128,22 @@ func (d *DBWrapper) BatchUpdate(values interface{}) error {
 	return tx.Commit()
 }
 
-func (d *DBWrapper) Save(record interface{}) error {
-	return d.Table(getTableName(record)).Save(record)
+func (d *DBWrapper) MultiSave(values interface{}) error {
+	return d.MultiSaveContext(context.Background(), values)
 }
 
-func (d *DBWrapper) MultiSave(values interface{}) error {
+func (d *DBWrapper) MultiSaveContext(ctx context.Context, values interface{}) error {
 	l := reflect.ValueOf(values)
 	if l.Kind() != reflect.Slice {
 		return errors.New("not slice")
 	}
 
-	tx, err := d.Begin()
+	tx, err := d.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
 	for i := 0; i < l.Len(); i++ {
-		err = tx.Save(l.Index(i).Interface())
+		err = tx.SaveContext(ctx, l.Index(i).Interface())
 		if err != nil {
 			tx.Rollback()
 			return err
@@ -136,11 +151,3 @@ func (d *DBWrapper) MultiSave(values interface{}) error {
 	}
 	return tx.Commit()
 }
-
-func (d *DBWrapper) Select(records interface{}, where string, args ...interface{}) error {
-	return d.Table(getTableNameBySlice(records)).Select(records, where, args...)
-}
-
-func (d *DBWrapper) SelectOne(record interface{}, where string, args ...interface{}) error {
-	return d.Table(getTableName(record)).SelectOne(record, where, args...)
-}
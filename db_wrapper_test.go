@@ -292,3 +292,40 @@ func TestExecutor_SelectOne(t *testing.T) {
 		}
 	}
 }
+
+func TestExecutor_Iterate(t *testing.T) {
+	{
+		it := _testDB.Table(&Product{}).Iterate("id>?", 1000)
+		var p Product
+		for it.Next(&p) {
+		}
+		if err := it.Err(); err != nil {
+			t.Error(err)
+			t.Fail()
+		}
+	}
+
+	{
+		err := _testDB.Each(func(p *Item) error {
+			if p.ItemID == nil {
+				t.Fail()
+			}
+			return nil
+		}, "id>?", 1000)
+		if err != nil {
+			t.Error(err)
+			t.Fail()
+		}
+	}
+}
+
+func TestExecutor_ErrNotFound(t *testing.T) {
+	_testDB.SetErrNotFound(true)
+	defer _testDB.SetErrNotFound(false)
+
+	err := _testDB.Delete(&Product{ProductID: ProductID{ID: -1}})
+	if err != sql.ErrNotFound {
+		t.Error(err)
+		t.Fail()
+	}
+}
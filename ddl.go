@@ -0,0 +1,160 @@
+package sql
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+
+	"github.com/gopub/log"
+)
+
+// DDLOption modifies CREATE/DROP statements
+type DDLOption int
+
+const (
+	// IfNotExists adds IF NOT EXISTS to CREATE statements
+	IfNotExists DDLOption = iota + 1
+
+	// IfExists adds IF EXISTS to DROP statement
+	IfExists
+)
+
+func hasDDLOption(opts []DDLOption, opt DDLOption) bool {
+	for _, o := range opts {
+		if o == opt {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateTable creates table and its indexes from record's struct type
+// e.g. db.Table("products").CreateTable(&Product{}, sql.IfNotExists)
+func (t *Table) CreateTable(record interface{}, opts ...DDLOption) error {
+	return t.CreateTableContext(context.Background(), record, opts...)
+}
+
+func (t *Table) CreateTableContext(ctx context.Context, record interface{}, opts ...DDLOption) error {
+	for _, query := range createTableQueries(t.dialect, t.name, structType(record), hasDDLOption(opts, IfNotExists)) {
+		if _, err := t.exec(ctx, query, nil); err != nil {
+			log.Error(err)
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *Table) DropTable(opts ...DDLOption) error {
+	return t.DropTableContext(context.Background(), opts...)
+}
+
+func (t *Table) DropTableContext(ctx context.Context, opts ...DDLOption) error {
+	query := "DROP TABLE "
+	if hasDDLOption(opts, IfExists) {
+		query += "IF EXISTS "
+	}
+	query += t.dialect.QuoteIdent(t.name)
+	_, err := t.exec(ctx, query, nil)
+	if err != nil {
+		log.Error(err)
+	}
+	return err
+}
+
+// createTableQueries returns CREATE TABLE statement, followed by CREATE INDEX statements if the dialect doesn't
+// support index definition in CREATE TABLE
+func createTableQueries(d Dialect, table string, typ reflect.Type, ifNotExists bool) []string {
+	info := getColumnInfo(typ)
+	var buf bytes.Buffer
+	buf.WriteString("CREATE TABLE ")
+	if ifNotExists {
+		buf.WriteString("IF NOT EXISTS ")
+	}
+	buf.WriteString(d.QuoteIdent(table))
+	buf.WriteString(" (")
+	for i, name := range info.names {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		buf.WriteString("\n\t")
+		buf.WriteString(columnDefinition(d, typ, info, name))
+	}
+	if len(info.pkNames) > 0 {
+		buf.WriteString(",\n\tPRIMARY KEY (")
+		buf.WriteString(quoteIdents(d, info.pkNames))
+		buf.WriteString(")")
+	}
+
+	// MySQL doesn't support CREATE INDEX IF NOT EXISTS, so indexes are defined in CREATE TABLE
+	inlineIndex := d.Name() == MySQL.Name()
+	if inlineIndex {
+		for _, name := range info.indexNames {
+			buf.WriteString(",\n\tINDEX ")
+			buf.WriteString(d.QuoteIdent(indexName(table, name)))
+			buf.WriteString(" (")
+			buf.WriteString(d.QuoteIdent(name))
+			buf.WriteString(")")
+		}
+	}
+	buf.WriteString("\n)")
+
+	queries := []string{buf.String()}
+	if !inlineIndex {
+		for _, name := range info.indexNames {
+			query := "CREATE INDEX "
+			if ifNotExists {
+				query += "IF NOT EXISTS "
+			}
+			query += d.QuoteIdent(indexName(table, name)) + " ON " + d.QuoteIdent(table) + " (" + d.QuoteIdent(name) + ")"
+			queries = append(queries, query)
+		}
+	}
+	return queries
+}
+
+func columnDef(typ reflect.Type, info *columnInfo, name string) *ColumnDef {
+	ft := typ.FieldByIndex(info.nameToIndex[name]).Type
+	if ft.Kind() == reflect.Ptr && IndexOfString(info.jsonNames, name) < 0 {
+		ft = ft.Elem()
+	}
+	return &ColumnDef{
+		Name:          name,
+		Type:          ft,
+		Size:          info.sizes[name],
+		JSON:          IndexOfString(info.jsonNames, name) >= 0,
+		AutoIncrement: name == info.aiName,
+	}
+}
+
+// declaredType returns column type in tag, or the type provided by dialect
+func declaredType(d Dialect, typ reflect.Type, info *columnInfo, name string) string {
+	if t, ok := info.types[name]; ok {
+		return t
+	}
+	return d.ColumnType(columnDef(typ, info, name))
+}
+
+func columnDefinition(d Dialect, typ reflect.Type, info *columnInfo, name string) string {
+	s := d.QuoteIdent(name) + " " + declaredType(d, typ, info, name)
+	if IndexOfString(info.nullableNames, name) < 0 {
+		s += " NOT NULL"
+	}
+	if def, ok := info.defaults[name]; ok {
+		s += " DEFAULT " + def
+	}
+	if IndexOfString(info.uniqueNames, name) >= 0 {
+		s += " UNIQUE"
+	}
+	return s
+}
+
+// indexName returns name of index on column, e.g. idx_products_name
+func indexName(table, column string) string {
+	return "idx_" + strings.Replace(table, ".", "_", -1) + "_" + column
+}
+
+// uniqueIndexName returns name of unique index on column, e.g. uniq_products_name
+func uniqueIndexName(table, column string) string {
+	return "uniq_" + strings.Replace(table, ".", "_", -1) + "_" + column
+}
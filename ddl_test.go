@@ -0,0 +1,60 @@
+package sql
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type ddlContent struct {
+	Title string
+}
+
+type ddlProduct struct {
+	ID        int64       `sql:"primary key,auto_increment"`
+	Name      string      `sql:"size=64,unique"`
+	Price     float64     `sql:"default=0,index"`
+	Text      *ddlContent `sql:"txt,json,nullable"`
+	Status    string      `sql:"default='ON'"`
+	CreatedAt time.Time   `sql:"created_at"`
+}
+
+func TestCreateTableQueries(t *testing.T) {
+	typ := reflect.TypeOf(ddlProduct{})
+	t.Run("MySQL", func(t *testing.T) {
+		queries := createTableQueries(MySQL, "products", typ, true)
+		require.Equal(t, []string{"CREATE TABLE IF NOT EXISTS `products` (\n" +
+			"\t`id` BIGINT AUTO_INCREMENT NOT NULL,\n" +
+			"\t`name` VARCHAR(64) NOT NULL UNIQUE,\n" +
+			"\t`price` DOUBLE NOT NULL DEFAULT 0,\n" +
+			"\t`txt` JSON,\n" +
+			"\t`status` VARCHAR(255) NOT NULL DEFAULT 'ON',\n" +
+			"\t`created_at` DATETIME NOT NULL,\n" +
+			"\tPRIMARY KEY (`id`),\n" +
+			"\tINDEX `idx_products_price` (`price`)\n)"}, queries)
+	})
+
+	t.Run("SQLite", func(t *testing.T) {
+		queries := createTableQueries(SQLite, "products", typ, false)
+		require.Equal(t, []string{`CREATE TABLE "products" (` + "\n" +
+			"\t\"id\" INTEGER NOT NULL,\n" +
+			"\t\"name\" VARCHAR(64) NOT NULL UNIQUE,\n" +
+			"\t\"price\" REAL NOT NULL DEFAULT 0,\n" +
+			"\t\"txt\" TEXT,\n" +
+			"\t\"status\" TEXT NOT NULL DEFAULT 'ON',\n" +
+			"\t\"created_at\" DATETIME NOT NULL,\n" +
+			"\tPRIMARY KEY (\"id\")\n)",
+			`CREATE INDEX "idx_products_price" ON "products" ("price")`}, queries)
+	})
+
+	t.Run("PostgreSQL", func(t *testing.T) {
+		queries := createTableQueries(PostgreSQL, "products", typ, true)
+		require.Len(t, queries, 2)
+		require.Contains(t, queries[0], "\t\"id\" BIGSERIAL NOT NULL,\n")
+		require.Contains(t, queries[0], "\t\"txt\" JSONB,\n")
+		require.Contains(t, queries[0], "\t\"created_at\" TIMESTAMPTZ NOT NULL,\n")
+		require.Equal(t, `CREATE INDEX IF NOT EXISTS "idx_products_price" ON "products" ("price")`, queries[1])
+	})
+}
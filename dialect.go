@@ -0,0 +1,541 @@
+package sql
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Dialect describes how SQL statements are rendered for a specific database
+type Dialect interface {
+	// Name returns dialect's name, e.g. mysql, sqlite, postgres
+	Name() string
+
+	// Placeholder returns the nth(starting from 1) bind variable
+	Placeholder(n int) string
+
+	// QuoteIdent quotes identifier of table or column. Qualified name like schema.table is quoted part by part
+	QuoteIdent(name string) string
+
+	// LimitOffset returns LIMIT/OFFSET clause. Non-positive value means no limit or offset
+	LimitOffset(limit, offset int64) string
+
+	// Upsert converts insert statement into a statement which updates columns in updateNames if primary key conflicts
+	Upsert(insert string, pkNames, updateNames []string) (string, error)
+
+	// Returning returns clause appended to insert statement to query generated column,
+	// or empty string if generated column is reported by Result.LastInsertId
+	Returning(column string) string
+
+	// BatchInsertID returns generated id of the ith row inserted by an n-row insert statement.
+	// It returns false if the id can't be derived from Result.LastInsertId
+	BatchInsertID(lastInsertID int64, i, n int) (int64, bool)
+
+	// MaxParams returns the maximum number of bind variables in a statement
+	MaxParams() int
+
+	// ColumnType returns type of column in CREATE TABLE statement
+	ColumnType(c *ColumnDef) string
+}
+
+// ColumnDef describes a column for DDL generation
+type ColumnDef struct {
+	Name string
+
+	// Type is type of struct field
+	Type reflect.Type
+
+	// Size is maximum length of string column. Zero means default size
+	Size int
+
+	JSON          bool
+	AutoIncrement bool
+}
+
+var (
+	MySQL      Dialect = mysqlDialect{}
+	SQLite     Dialect = sqliteDialect{}
+	PostgreSQL Dialect = postgresDialect{}
+
+	// MySQL8 is the same as MySQL, except that Upsert refers to new values by row alias instead of VALUES(col)
+	// which is deprecated since MySQL 8.0.20. It requires MySQL 8.0.19 or later, and doesn't work with MariaDB.
+	// Use it by sql.RegisterDialect("mysql", sql.MySQL8)
+	MySQL8 Dialect = mysqlDialect{rowAlias: true}
+
+	// Generic renders ANSI SQL with ? placeholders. It's used for drivers without registered dialects, and doesn't support Upsert
+	Generic Dialect = genericDialect{}
+)
+
+var _dialectsMu sync.RWMutex
+var _dialects = map[string]Dialect{
+	"mysql":      MySQL,
+	"sqlite3":    SQLite,
+	"sqlite":     SQLite,
+	"postgres":   PostgreSQL,
+	"postgresql": PostgreSQL,
+	"pgx":        PostgreSQL,
+}
+
+// RegisterDialect makes dialect available for driverName
+func RegisterDialect(driverName string, d Dialect) {
+	if d == nil {
+		panic("dialect is nil")
+	}
+	_dialectsMu.Lock()
+	_dialects[driverName] = d
+	_dialectsMu.Unlock()
+}
+
+// GetDialect returns dialect registered for driverName, or nil if not found
+func GetDialect(driverName string) Dialect {
+	_dialectsMu.RLock()
+	defer _dialectsMu.RUnlock()
+	return _dialects[driverName]
+}
+
+type mysqlDialect struct {
+	rowAlias bool
+}
+
+func (d mysqlDialect) Name() string {
+	return "mysql"
+}
+
+func (d mysqlDialect) Placeholder(n int) string {
+	return "?"
+}
+
+func (d mysqlDialect) QuoteIdent(name string) string {
+	return quoteIdent(name, '`')
+}
+
+func (d mysqlDialect) LimitOffset(limit, offset int64) string {
+	if limit <= 0 && offset <= 0 {
+		return ""
+	}
+	if limit <= 0 {
+		// MySQL requires LIMIT before OFFSET. This is the maximum value documented by MySQL
+		return fmt.Sprintf("LIMIT 18446744073709551615 OFFSET %d", offset)
+	}
+	if offset <= 0 {
+		return fmt.Sprintf("LIMIT %d", limit)
+	}
+	return fmt.Sprintf("LIMIT %d OFFSET %d", limit, offset)
+}
+
+func (d mysqlDialect) Upsert(insert string, pkNames, updateNames []string) (string, error) {
+	if len(updateNames) == 0 {
+		// Nothing to update, just keep the existing row
+		updateNames = pkNames
+	}
+	var buf bytes.Buffer
+	buf.WriteString(insert)
+	if d.rowAlias {
+		buf.WriteString(" AS ")
+		buf.WriteString(d.QuoteIdent("new"))
+	}
+	buf.WriteString(" ON DUPLICATE KEY UPDATE ")
+	for i, name := range updateNames {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		name = d.QuoteIdent(name)
+		buf.WriteString(name)
+		if d.rowAlias {
+			buf.WriteString(" = ")
+			buf.WriteString(d.QuoteIdent("new"))
+			buf.WriteString(".")
+			buf.WriteString(name)
+		} else {
+			buf.WriteString(" = VALUES(")
+			buf.WriteString(name)
+			buf.WriteString(")")
+		}
+	}
+	return buf.String(), nil
+}
+
+func (d mysqlDialect) Returning(column string) string {
+	return ""
+}
+
+// BatchInsertID returns false. LastInsertId is the id of the first row, but ids of a multi-row insert are not consecutive
+// if innodb_autoinc_lock_mode is 2 which is the default since MySQL 8.0, or auto_increment_increment isn't 1
+func (d mysqlDialect) BatchInsertID(lastInsertID int64, i, n int) (int64, bool) {
+	return 0, false
+}
+
+func (d mysqlDialect) MaxParams() int {
+	return 65535
+}
+
+func (d mysqlDialect) ColumnType(c *ColumnDef) string {
+	if c.JSON {
+		return "JSON"
+	}
+	if c.Type == _timeType {
+		return "DATETIME"
+	}
+	var typ string
+	switch c.Type.Kind() {
+	case reflect.Bool:
+		return "BOOL"
+	case reflect.Int8, reflect.Uint8:
+		typ = "TINYINT"
+	case reflect.Int16, reflect.Uint16:
+		typ = "SMALLINT"
+	case reflect.Int32, reflect.Uint32:
+		typ = "INT"
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+		typ = "BIGINT"
+	case reflect.Float32:
+		return "FLOAT"
+	case reflect.Float64:
+		return "DOUBLE"
+	case reflect.String:
+		if c.Size > 0 {
+			return fmt.Sprintf("VARCHAR(%d)", c.Size)
+		}
+		// Indexed column can't be TEXT
+		return "VARCHAR(255)"
+	default:
+		return "BLOB"
+	}
+	switch c.Type.Kind() {
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint, reflect.Uint64:
+		typ += " UNSIGNED"
+	}
+	if c.AutoIncrement {
+		typ += " AUTO_INCREMENT"
+	}
+	return typ
+}
+
+type sqliteDialect struct{}
+
+func (d sqliteDialect) Name() string {
+	return "sqlite"
+}
+
+func (d sqliteDialect) Placeholder(n int) string {
+	return "?"
+}
+
+func (d sqliteDialect) QuoteIdent(name string) string {
+	return quoteIdent(name, '"')
+}
+
+func (d sqliteDialect) LimitOffset(limit, offset int64) string {
+	if limit <= 0 && offset <= 0 {
+		return ""
+	}
+	if limit <= 0 {
+		return fmt.Sprintf("LIMIT -1 OFFSET %d", offset)
+	}
+	if offset <= 0 {
+		return fmt.Sprintf("LIMIT %d", limit)
+	}
+	return fmt.Sprintf("LIMIT %d OFFSET %d", limit, offset)
+}
+
+// Upsert uses ON CONFLICT clause which requires SQLite 3.24.0, so that columns not in updateNames are kept.
+// INSERT OR REPLACE is used if there is no primary key
+func (d sqliteDialect) Upsert(insert string, pkNames, updateNames []string) (string, error) {
+	if len(pkNames) == 0 {
+		return strings.Replace(insert, "INSERT INTO", "INSERT OR REPLACE INTO", 1), nil
+	}
+	var buf bytes.Buffer
+	buf.WriteString(insert)
+	buf.WriteString(" ON CONFLICT (")
+	buf.WriteString(quoteIdents(d, pkNames))
+	if len(updateNames) == 0 {
+		buf.WriteString(") DO NOTHING")
+		return buf.String(), nil
+	}
+	buf.WriteString(") DO UPDATE SET ")
+	for i, name := range updateNames {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		name = d.QuoteIdent(name)
+		buf.WriteString(name)
+		buf.WriteString(" = excluded.")
+		buf.WriteString(name)
+	}
+	return buf.String(), nil
+}
+
+func (d sqliteDialect) Returning(column string) string {
+	return ""
+}
+
+// BatchInsertID returns id of the ith row. LastInsertId is the rowid of the last row,
+// and rowids are consecutive as writers are serialized
+func (d sqliteDialect) BatchInsertID(lastInsertID int64, i, n int) (int64, bool) {
+	return lastInsertID - int64(n-1-i), true
+}
+
+// MaxParams returns SQLITE_MAX_VARIABLE_NUMBER of versions prior to 3.32.0, which is the lower bound
+func (d sqliteDialect) MaxParams() int {
+	return 999
+}
+
+// ColumnType returns type name of which the affinity matches Go type.
+// INTEGER primary key is an alias of rowid, so that auto increment column is generated
+func (d sqliteDialect) ColumnType(c *ColumnDef) string {
+	if c.JSON {
+		return "TEXT"
+	}
+	if c.Type == _timeType {
+		return "DATETIME"
+	}
+	switch c.Type.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "INTEGER"
+	case reflect.Float32, reflect.Float64:
+		return "REAL"
+	case reflect.String:
+		if c.Size > 0 {
+			return fmt.Sprintf("VARCHAR(%d)", c.Size)
+		}
+		return "TEXT"
+	default:
+		return "BLOB"
+	}
+}
+
+type postgresDialect struct{}
+
+func (d postgresDialect) Name() string {
+	return "postgres"
+}
+
+func (d postgresDialect) Placeholder(n int) string {
+	return fmt.Sprintf("$%d", n)
+}
+
+func (d postgresDialect) QuoteIdent(name string) string {
+	return quoteIdent(name, '"')
+}
+
+func (d postgresDialect) LimitOffset(limit, offset int64) string {
+	if limit <= 0 && offset <= 0 {
+		return ""
+	}
+	if limit <= 0 {
+		return fmt.Sprintf("OFFSET %d", offset)
+	}
+	if offset <= 0 {
+		return fmt.Sprintf("LIMIT %d", limit)
+	}
+	return fmt.Sprintf("LIMIT %d OFFSET %d", limit, offset)
+}
+
+func (d postgresDialect) Upsert(insert string, pkNames, updateNames []string) (string, error) {
+	if len(pkNames) == 0 {
+		return "", errors.New("no primary key")
+	}
+	if len(updateNames) == 0 {
+		// DO NOTHING returns no row for RETURNING clause, so update primary key with the same value
+		updateNames = pkNames
+	}
+	var buf bytes.Buffer
+	buf.WriteString(insert)
+	buf.WriteString(" ON CONFLICT (")
+	buf.WriteString(quoteIdents(d, pkNames))
+	buf.WriteString(") DO UPDATE SET ")
+	for i, name := range updateNames {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		name = d.QuoteIdent(name)
+		buf.WriteString(name)
+		buf.WriteString(" = EXCLUDED.")
+		buf.WriteString(name)
+	}
+	return buf.String(), nil
+}
+
+func (d postgresDialect) Returning(column string) string {
+	return "RETURNING " + d.QuoteIdent(column)
+}
+
+func (d postgresDialect) BatchInsertID(lastInsertID int64, i, n int) (int64, bool) {
+	return 0, false
+}
+
+func (d postgresDialect) MaxParams() int {
+	return 65535
+}
+
+func (d postgresDialect) ColumnType(c *ColumnDef) string {
+	if c.JSON {
+		return "JSONB"
+	}
+	if c.Type == _timeType {
+		return "TIMESTAMPTZ"
+	}
+	switch c.Type.Kind() {
+	case reflect.Bool:
+		return "BOOLEAN"
+	case reflect.Int8, reflect.Int16, reflect.Uint8:
+		return "SMALLINT"
+	case reflect.Int32, reflect.Uint16:
+		if c.AutoIncrement {
+			return "SERIAL"
+		}
+		return "INTEGER"
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		if c.AutoIncrement {
+			return "BIGSERIAL"
+		}
+		return "BIGINT"
+	case reflect.Float32:
+		return "REAL"
+	case reflect.Float64:
+		return "DOUBLE PRECISION"
+	case reflect.String:
+		if c.Size > 0 {
+			return fmt.Sprintf("VARCHAR(%d)", c.Size)
+		}
+		return "TEXT"
+	default:
+		return "BYTEA"
+	}
+}
+
+type genericDialect struct{}
+
+func (d genericDialect) Name() string {
+	return "generic"
+}
+
+func (d genericDialect) Placeholder(n int) string {
+	return "?"
+}
+
+func (d genericDialect) QuoteIdent(name string) string {
+	return quoteIdent(name, '"')
+}
+
+// LimitOffset returns OFFSET and FETCH clause of SQL:2008
+func (d genericDialect) LimitOffset(limit, offset int64) string {
+	var l []string
+	if offset > 0 {
+		l = append(l, fmt.Sprintf("OFFSET %d ROWS", offset))
+	}
+	if limit > 0 {
+		l = append(l, fmt.Sprintf("FETCH FIRST %d ROWS ONLY", limit))
+	}
+	return strings.Join(l, " ")
+}
+
+func (d genericDialect) Upsert(insert string, pkNames, updateNames []string) (string, error) {
+	return "", errors.New("upsert is not supported by generic dialect")
+}
+
+func (d genericDialect) Returning(column string) string {
+	return ""
+}
+
+func (d genericDialect) BatchInsertID(lastInsertID int64, i, n int) (int64, bool) {
+	return 0, false
+}
+
+func (d genericDialect) MaxParams() int {
+	return 999
+}
+
+func (d genericDialect) ColumnType(c *ColumnDef) string {
+	if c.JSON {
+		return "CLOB"
+	}
+	if c.Type == _timeType {
+		return "TIMESTAMP"
+	}
+	var typ string
+	switch c.Type.Kind() {
+	case reflect.Bool:
+		return "BOOLEAN"
+	case reflect.Int8, reflect.Int16, reflect.Uint8:
+		typ = "SMALLINT"
+	case reflect.Int32, reflect.Uint16:
+		typ = "INTEGER"
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		typ = "BIGINT"
+	case reflect.Float32:
+		return "REAL"
+	case reflect.Float64:
+		return "DOUBLE PRECISION"
+	case reflect.String:
+		if c.Size > 0 {
+			return fmt.Sprintf("VARCHAR(%d)", c.Size)
+		}
+		return "VARCHAR(255)"
+	default:
+		return "BLOB"
+	}
+	if c.AutoIncrement {
+		typ += " GENERATED BY DEFAULT AS IDENTITY"
+	}
+	return typ
+}
+
+func quoteIdent(name string, quote byte) string {
+	if len(name) == 0 || name[0] == quote {
+		return name
+	}
+	q := string(quote)
+	parts := strings.Split(name, ".")
+	for i, p := range parts {
+		if p == "*" {
+			continue
+		}
+		parts[i] = q + strings.Replace(p, q, q+q, -1) + q
+	}
+	return strings.Join(parts, ".")
+}
+
+func quoteIdents(d Dialect, names []string) string {
+	var b strings.Builder
+	for i, name := range names {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(d.QuoteIdent(name))
+	}
+	return b.String()
+}
+
+// bindVars replaces ? in query with dialect's placeholders. Question marks in quoted strings or identifiers are skipped
+func bindVars(d Dialect, query string) string {
+	if d.Placeholder(1) == "?" || strings.IndexByte(query, '?') < 0 {
+		return query
+	}
+
+	var b strings.Builder
+	b.Grow(len(query) + 8)
+	n := 0
+	var quote byte
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '?':
+			n++
+			b.WriteString(d.Placeholder(n))
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
@@ -0,0 +1,90 @@
+package sql_test
+
+import (
+	"testing"
+
+	"github.com/gopub/sql"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetDialect(t *testing.T) {
+	require.Equal(t, sql.MySQL, sql.GetDialect("mysql"))
+	require.Equal(t, sql.SQLite, sql.GetDialect("sqlite3"))
+	require.Equal(t, sql.SQLite, sql.GetDialect("sqlite"))
+	require.Equal(t, sql.PostgreSQL, sql.GetDialect("postgres"))
+	require.Equal(t, sql.PostgreSQL, sql.GetDialect("pgx"))
+	require.Nil(t, sql.GetDialect("unknown"))
+}
+
+func TestDialect(t *testing.T) {
+	t.Run("Placeholder", func(t *testing.T) {
+		require.Equal(t, "?", sql.MySQL.Placeholder(2))
+		require.Equal(t, "?", sql.SQLite.Placeholder(2))
+		require.Equal(t, "$2", sql.PostgreSQL.Placeholder(2))
+	})
+
+	t.Run("QuoteIdent", func(t *testing.T) {
+		require.Equal(t, "`products`", sql.MySQL.QuoteIdent("products"))
+		require.Equal(t, "`test`.`products`", sql.MySQL.QuoteIdent("test.products"))
+		require.Equal(t, `"products"`, sql.SQLite.QuoteIdent("products"))
+		require.Equal(t, `"a""b"`, sql.PostgreSQL.QuoteIdent(`a"b`))
+	})
+
+	t.Run("LimitOffset", func(t *testing.T) {
+		require.Equal(t, "", sql.MySQL.LimitOffset(0, 0))
+		require.Equal(t, "LIMIT 10", sql.MySQL.LimitOffset(10, 0))
+		require.Equal(t, "LIMIT 10 OFFSET 20", sql.PostgreSQL.LimitOffset(10, 20))
+		require.Equal(t, "LIMIT -1 OFFSET 20", sql.SQLite.LimitOffset(0, 20))
+		require.Equal(t, "OFFSET 20", sql.PostgreSQL.LimitOffset(0, 20))
+		require.Equal(t, "OFFSET 20 ROWS FETCH FIRST 10 ROWS ONLY", sql.Generic.LimitOffset(10, 20))
+		require.Equal(t, "FETCH FIRST 10 ROWS ONLY", sql.Generic.LimitOffset(10, 0))
+	})
+
+	t.Run("Upsert", func(t *testing.T) {
+		insert := "INSERT INTO t(id, name) VALUES (?, ?)"
+		query, err := sql.MySQL.Upsert(insert, []string{"id"}, []string{"name"})
+		require.NoError(t, err)
+		require.Equal(t, insert+" ON DUPLICATE KEY UPDATE `name` = VALUES(`name`)", query)
+
+		query, err = sql.MySQL8.Upsert(insert, []string{"id"}, []string{"name"})
+		require.NoError(t, err)
+		require.Equal(t, insert+" AS `new` ON DUPLICATE KEY UPDATE `name` = `new`.`name`", query)
+		require.Equal(t, sql.MySQL.Name(), sql.MySQL8.Name())
+
+		query, err = sql.SQLite.Upsert(insert, []string{"id"}, []string{"name"})
+		require.NoError(t, err)
+		require.Equal(t, `INSERT INTO t(id, name) VALUES (?, ?) ON CONFLICT ("id") DO UPDATE SET "name" = excluded."name"`, query)
+
+		query, err = sql.SQLite.Upsert(insert, nil, []string{"name"})
+		require.NoError(t, err)
+		require.Equal(t, "INSERT OR REPLACE INTO t(id, name) VALUES (?, ?)", query)
+
+		query, err = sql.PostgreSQL.Upsert(insert, []string{"id"}, []string{"name"})
+		require.NoError(t, err)
+		require.Equal(t, insert+` ON CONFLICT ("id") DO UPDATE SET "name" = EXCLUDED."name"`, query)
+
+		_, err = sql.PostgreSQL.Upsert(insert, nil, []string{"name"})
+		require.Error(t, err)
+
+		_, err = sql.Generic.Upsert(insert, []string{"id"}, []string{"name"})
+		require.Error(t, err)
+	})
+
+	t.Run("BatchInsertID", func(t *testing.T) {
+		_, ok := sql.MySQL.BatchInsertID(10, 2, 5)
+		require.False(t, ok)
+
+		id, ok := sql.SQLite.BatchInsertID(14, 2, 5)
+		require.True(t, ok)
+		require.Equal(t, int64(12), id)
+
+		_, ok = sql.PostgreSQL.BatchInsertID(14, 2, 5)
+		require.False(t, ok)
+	})
+
+	t.Run("Returning", func(t *testing.T) {
+		require.Empty(t, sql.MySQL.Returning("id"))
+		require.Empty(t, sql.SQLite.Returning("id"))
+		require.Equal(t, `RETURNING "id"`, sql.PostgreSQL.Returning("id"))
+	})
+}
@@ -1,6 +1,6 @@
 module github.com/gopub/sql
 
-go 1.14
+go 1.16
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
@@ -11,6 +11,7 @@ require (
 	github.com/gopub/types v0.2.22
 	github.com/jinzhu/inflection v1.0.0
 	github.com/kr/pretty v0.1.0 // indirect
+	github.com/mattn/go-sqlite3 v1.14.16
 	github.com/nyaruka/phonenumbers v1.0.56 // indirect
 	github.com/shopspring/decimal v1.2.0
 	github.com/stretchr/testify v1.6.1
@@ -0,0 +1,274 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ColumnSchema is a column of an existing table
+type ColumnSchema struct {
+	Name     string
+	Type     string
+	Nullable bool
+}
+
+// Inspector reads schema of existing tables. Dialects implement it to support AutoMigrate
+type Inspector interface {
+	// Columns returns columns of table, or empty slice if table doesn't exist
+	Columns(ctx context.Context, exe Executor, table string) ([]*ColumnSchema, error)
+
+	// Indexes returns index names of table
+	Indexes(ctx context.Context, exe Executor, table string) ([]string, error)
+
+	// UniqueColumns returns columns which have single column unique indexes, including unique constraints and primary key
+	UniqueColumns(ctx context.Context, exe Executor, table string) ([]string, error)
+
+	// EqualType returns true if declared type in CREATE TABLE statement is the same as actual type returned by Columns
+	EqualType(declared, actual string) bool
+}
+
+// splitSchema splits qualified table name into schema and table
+func splitSchema(table string) (string, string) {
+	if i := strings.LastIndex(table, "."); i >= 0 {
+		return table[:i], table[i+1:]
+	}
+	return "", table
+}
+
+func queryStrings(ctx context.Context, exe Executor, query string, args ...interface{}) ([]string, error) {
+	rows, err := exe.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var l []string
+	for rows.Next() {
+		var s string
+		if err = rows.Scan(&s); err != nil {
+			return nil, err
+		}
+		l = append(l, s)
+	}
+	return l, rows.Err()
+}
+
+func queryColumns(ctx context.Context, exe Executor, query string, args ...interface{}) ([]*ColumnSchema, error) {
+	rows, err := exe.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var l []*ColumnSchema
+	for rows.Next() {
+		c := new(ColumnSchema)
+		var nullable string
+		if err = rows.Scan(&c.Name, &c.Type, &nullable); err != nil {
+			return nil, err
+		}
+		c.Nullable = strings.EqualFold(nullable, "YES")
+		l = append(l, c)
+	}
+	return l, rows.Err()
+}
+
+var _ Inspector = mysqlDialect{}
+
+func (d mysqlDialect) Columns(ctx context.Context, exe Executor, table string) ([]*ColumnSchema, error) {
+	schema, table := splitSchema(table)
+	query := "SELECT column_name, column_type, is_nullable FROM information_schema.columns " +
+		"WHERE table_schema = COALESCE(NULLIF(?, ''), DATABASE()) AND table_name = ? ORDER BY ordinal_position"
+	return queryColumns(ctx, exe, query, schema, table)
+}
+
+func (d mysqlDialect) Indexes(ctx context.Context, exe Executor, table string) ([]string, error) {
+	schema, table := splitSchema(table)
+	query := "SELECT DISTINCT index_name FROM information_schema.statistics " +
+		"WHERE table_schema = COALESCE(NULLIF(?, ''), DATABASE()) AND table_name = ?"
+	return queryStrings(ctx, exe, query, schema, table)
+}
+
+func (d mysqlDialect) UniqueColumns(ctx context.Context, exe Executor, table string) ([]string, error) {
+	schema, table := splitSchema(table)
+	query := "SELECT MIN(column_name) FROM information_schema.statistics " +
+		"WHERE table_schema = COALESCE(NULLIF(?, ''), DATABASE()) AND table_name = ? AND non_unique = 0 " +
+		"GROUP BY index_name HAVING COUNT(*) = 1"
+	return queryStrings(ctx, exe, query, schema, table)
+}
+
+var _mysqlIntWidthRegexp = regexp.MustCompile(`^(tinyint|smallint|mediumint|int|bigint)\(\d+\)`)
+
+func (d mysqlDialect) EqualType(declared, actual string) bool {
+	normalize := func(s string) string {
+		s = strings.ToLower(strings.TrimSpace(s))
+		s = strings.TrimSuffix(s, " auto_increment")
+		// Display width is removed since MySQL 8.0.17
+		s = _mysqlIntWidthRegexp.ReplaceAllString(s, "$1")
+		if s == "bool" || s == "boolean" {
+			s = "tinyint"
+		}
+		return s
+	}
+	return normalize(declared) == normalize(actual)
+}
+
+var _ Inspector = sqliteDialect{}
+
+func (d sqliteDialect) Columns(ctx context.Context, exe Executor, table string) ([]*ColumnSchema, error) {
+	query := "PRAGMA table_info(" + d.QuoteIdent(table) + ")"
+	if schema, name := splitSchema(table); len(schema) > 0 {
+		query = "PRAGMA " + d.QuoteIdent(schema) + ".table_info(" + d.QuoteIdent(name) + ")"
+	}
+	rows, err := exe.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var l []*ColumnSchema
+	for rows.Next() {
+		var cid, notNull, pk int
+		var dflt sql.NullString
+		c := new(ColumnSchema)
+		if err = rows.Scan(&cid, &c.Name, &c.Type, &notNull, &dflt, &pk); err != nil {
+			return nil, err
+		}
+		c.Nullable = notNull == 0
+		l = append(l, c)
+	}
+	return l, rows.Err()
+}
+
+func (d sqliteDialect) Indexes(ctx context.Context, exe Executor, table string) ([]string, error) {
+	schema, name := splitSchema(table)
+	query := "SELECT name FROM sqlite_master WHERE type = 'index' AND tbl_name = ?"
+	if len(schema) > 0 {
+		query = "SELECT name FROM " + d.QuoteIdent(schema) + ".sqlite_master WHERE type = 'index' AND tbl_name = ?"
+	}
+	return queryStrings(ctx, exe, query, name)
+}
+
+func (d sqliteDialect) UniqueColumns(ctx context.Context, exe Executor, table string) ([]string, error) {
+	prefix := ""
+	schema, name := splitSchema(table)
+	if len(schema) > 0 {
+		prefix = d.QuoteIdent(schema) + "."
+	}
+	// Columns of index_list are seq, name, unique, origin and partial
+	var indexes []string
+	err := d.queryPragma(ctx, exe, "PRAGMA "+prefix+"index_list("+d.QuoteIdent(name)+")", func(values []interface{}) {
+		if fmt.Sprint(values[2]) == "1" {
+			indexes = append(indexes, fmt.Sprintf("%s", values[1]))
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Columns of index_info are seqno, cid and name
+	var l []string
+	for _, index := range indexes {
+		var columns []string
+		err = d.queryPragma(ctx, exe, "PRAGMA "+prefix+"index_info("+d.QuoteIdent(index)+")", func(values []interface{}) {
+			columns = append(columns, fmt.Sprintf("%s", values[2]))
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(columns) == 1 {
+			l = append(l, columns[0])
+		}
+	}
+	return l, nil
+}
+
+// queryPragma calls f with values of each row, as columns of pragma vary with SQLite versions
+func (d sqliteDialect) queryPragma(ctx context.Context, exe Executor, query string, f func(values []interface{})) error {
+	rows, err := exe.QueryContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		ptrs := make([]interface{}, len(columns))
+		for i := range values {
+			ptrs[i] = &values[i]
+		}
+		if err = rows.Scan(ptrs...); err != nil {
+			return err
+		}
+		f(values)
+	}
+	return rows.Err()
+}
+
+func (d sqliteDialect) EqualType(declared, actual string) bool {
+	return strings.EqualFold(strings.TrimSpace(declared), strings.TrimSpace(actual))
+}
+
+var _ Inspector = postgresDialect{}
+
+func (d postgresDialect) Columns(ctx context.Context, exe Executor, table string) ([]*ColumnSchema, error) {
+	schema, table := splitSchema(table)
+	query := "SELECT column_name, data_type, is_nullable FROM information_schema.columns " +
+		"WHERE table_schema = COALESCE(NULLIF($1, ''), current_schema()) AND table_name = $2 ORDER BY ordinal_position"
+	return queryColumns(ctx, exe, query, schema, table)
+}
+
+func (d postgresDialect) Indexes(ctx context.Context, exe Executor, table string) ([]string, error) {
+	schema, table := splitSchema(table)
+	query := "SELECT indexname FROM pg_indexes WHERE schemaname = COALESCE(NULLIF($1, ''), current_schema()) AND tablename = $2"
+	return queryStrings(ctx, exe, query, schema, table)
+}
+
+func (d postgresDialect) UniqueColumns(ctx context.Context, exe Executor, table string) ([]string, error) {
+	schema, table := splitSchema(table)
+	query := "SELECT a.attname FROM pg_index i JOIN pg_class c ON c.oid = i.indrelid " +
+		"JOIN pg_namespace n ON n.oid = c.relnamespace JOIN pg_attribute a ON a.attrelid = c.oid AND a.attnum = i.indkey[0] " +
+		"WHERE i.indisunique AND i.indnatts = 1 AND n.nspname = COALESCE(NULLIF($1, ''), current_schema()) AND c.relname = $2"
+	return queryStrings(ctx, exe, query, schema, table)
+}
+
+// _postgresTypes maps declared types to data_type of information_schema.columns
+var _postgresTypes = map[string]string{
+	"smallserial": "smallint",
+	"serial":      "integer",
+	"bigserial":   "bigint",
+	"int":         "integer",
+	"int4":        "integer",
+	"int8":        "bigint",
+	"bool":        "boolean",
+	"float8":      "double precision",
+	"timestamptz": "timestamp with time zone",
+	"timestamp":   "timestamp without time zone",
+	"varchar":     "character varying",
+}
+
+func (d postgresDialect) EqualType(declared, actual string) bool {
+	declared = strings.ToLower(strings.TrimSpace(declared))
+	// data_type doesn't include length
+	if i := strings.Index(declared, "("); i >= 0 {
+		declared = declared[:i]
+	}
+	if t, ok := _postgresTypes[declared]; ok {
+		declared = t
+	}
+	return declared == strings.ToLower(strings.TrimSpace(actual))
+}
+
+// SchemaDiff is a destructive difference between struct and existing table which is not applied by AutoMigrate
+type SchemaDiff struct {
+	Table  string
+	Column string
+	Reason string
+}
+
+func (d *SchemaDiff) String() string {
+	return fmt.Sprintf("%s.%s: %s", d.Table, d.Column, d.Reason)
+}
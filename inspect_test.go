@@ -0,0 +1,24 @@
+package sql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInspector_EqualType(t *testing.T) {
+	require.True(t, mysqlDialect{}.EqualType("BIGINT AUTO_INCREMENT", "bigint(20)"))
+	require.True(t, mysqlDialect{}.EqualType("BIGINT UNSIGNED", "bigint unsigned"))
+	require.True(t, mysqlDialect{}.EqualType("BOOL", "tinyint(1)"))
+	require.True(t, mysqlDialect{}.EqualType("VARCHAR(64)", "varchar(64)"))
+	require.False(t, mysqlDialect{}.EqualType("VARCHAR(64)", "varchar(255)"))
+
+	require.True(t, sqliteDialect{}.EqualType("INTEGER", "integer"))
+	require.False(t, sqliteDialect{}.EqualType("TEXT", "VARCHAR(64)"))
+
+	require.True(t, postgresDialect{}.EqualType("BIGSERIAL", "bigint"))
+	require.True(t, postgresDialect{}.EqualType("VARCHAR(64)", "character varying"))
+	require.True(t, postgresDialect{}.EqualType("TIMESTAMPTZ", "timestamp with time zone"))
+	require.True(t, postgresDialect{}.EqualType("DOUBLE PRECISION", "double precision"))
+	require.False(t, postgresDialect{}.EqualType("TEXT", "bigint"))
+}
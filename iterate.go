@@ -0,0 +1,159 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+
+	"github.com/gopub/log"
+)
+
+// Iterator reads rows one by one instead of loading all rows into memory. It must be closed if Next doesn't return false.
+//
+//	it := db.Table(&Product{}).Iterate("price < ?", 0.2)
+//	defer it.Close()
+//	var p Product
+//	for it.Next(&p) {
+//	    ...
+//	}
+//	err := it.Err()
+type Iterator struct {
+	ctx   context.Context
+	query *Query
+	rows  *sql.Rows
+	typ   reflect.Type
+	info  *columnInfo
+	err   error
+	done  bool
+}
+
+// Next reads next row into record which is a pointer to struct, or pointer to pointer to struct.
+// Columns are selected by type of record in the first call, and record of another type is not allowed.
+// It returns false if there are no more rows or an error occurred
+func (it *Iterator) Next(record interface{}) bool {
+	if it.done {
+		return false
+	}
+
+	rv := reflect.ValueOf(record)
+	if rv.Kind() != reflect.Ptr {
+		panic("not pointer to a struct")
+	}
+
+	if it.rows == nil {
+		it.typ = rv.Elem().Type()
+		typ := it.typ
+		if typ.Kind() == reflect.Ptr {
+			typ = typ.Elem()
+		}
+		if typ.Kind() != reflect.Struct {
+			panic("not pointer to a struct")
+		}
+		it.info = getColumnInfo(typ)
+		query, args := it.query.scoped(it.info).selectQuery(quoteIdents(it.query.table.dialect, it.info.names))
+		it.rows, it.err = it.query.table.query(it.ctx, query, args)
+		if it.err != nil {
+			log.Error(it.err)
+			it.done = true
+			return false
+		}
+	} else if rv.Elem().Type() != it.typ {
+		panic("record type changed: " + rv.Elem().Type().String())
+	}
+
+	if !it.rows.Next() {
+		it.err = it.rows.Err()
+		it.Close()
+		return false
+	}
+
+	ev := newRecord(it.typ).Elem()
+	elem := ev
+	if elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+	if it.err = it.rows.Scan(scanDests(elem, it.info)...); it.err != nil {
+		log.Error(it.err)
+		it.Close()
+		return false
+	}
+	track(elem, it.info, it.info.names)
+	rv.Elem().Set(ev)
+	return true
+}
+
+// Err returns the error occurred during iteration
+func (it *Iterator) Err() error {
+	return it.err
+}
+
+// Close closes the iterator. It's safe to call Close multiple times
+func (it *Iterator) Close() error {
+	it.done = true
+	if it.rows == nil {
+		return nil
+	}
+	return it.rows.Close()
+}
+
+// Iterate returns an iterator of rows matching conditions
+func (q *Query) Iterate() *Iterator {
+	return q.IterateContext(context.Background())
+}
+
+func (q *Query) IterateContext(ctx context.Context) *Iterator {
+	return &Iterator{ctx: ctx, query: q}
+}
+
+var _errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// Each calls fn with every row, and stops if fn returns an error. fn is in format of func(record *Product) error.
+// A new record is passed in each call
+func (q *Query) Each(fn interface{}) error {
+	return q.EachContext(context.Background(), fn)
+}
+
+func (q *Query) EachContext(ctx context.Context, fn interface{}) error {
+	fv := reflect.ValueOf(fn)
+	recordType := eachRecordType(fv.Type())
+	it := q.IterateContext(ctx)
+	defer it.Close()
+	for {
+		p := reflect.New(recordType)
+		if !it.Next(p.Interface()) {
+			break
+		}
+		if out := fv.Call([]reflect.Value{p.Elem()}); !out[0].IsNil() {
+			return out[0].Interface().(error)
+		}
+	}
+	return it.Err()
+}
+
+// eachRecordType returns record type of fn which is func(*Struct) error
+func eachRecordType(fn reflect.Type) reflect.Type {
+	if fn == nil || fn.Kind() != reflect.Func || fn.NumIn() != 1 || fn.NumOut() != 1 || fn.Out(0) != _errorType {
+		panic("fn must be in format of func(*Struct) error")
+	}
+	typ := fn.In(0)
+	if typ.Kind() != reflect.Ptr || typ.Elem().Kind() != reflect.Struct {
+		panic("fn must be in format of func(*Struct) error")
+	}
+	return typ
+}
+
+func (t *Table) Iterate(where interface{}, args ...interface{}) *Iterator {
+	return t.IterateContext(context.Background(), where, args...)
+}
+
+func (t *Table) IterateContext(ctx context.Context, where interface{}, args ...interface{}) *Iterator {
+	return t.Where(where, args...).IterateContext(ctx)
+}
+
+func (t *Table) Each(fn interface{}, where interface{}, args ...interface{}) error {
+	return t.EachContext(context.Background(), fn, where, args...)
+}
+
+func (t *Table) EachContext(ctx context.Context, fn interface{}, where interface{}, args ...interface{}) error {
+	return t.Where(where, args...).EachContext(ctx, fn)
+}
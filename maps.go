@@ -0,0 +1,154 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gopub/log"
+)
+
+// SelectMaps returns rows matching conditions as maps of column names to values.
+// []byte values returned by driver are converted into numbers or strings by column types, see normalizeValue
+func (q *Query) SelectMaps() ([]map[string]interface{}, error) {
+	return q.SelectMapsContext(context.Background())
+}
+
+func (q *Query) SelectMapsContext(ctx context.Context) ([]map[string]interface{}, error) {
+	query, args := q.scoped(q.table.info()).selectQuery("*")
+	rows, err := q.table.query(ctx, query, args)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	var l []map[string]interface{}
+	values := make([]interface{}, len(columnTypes))
+	dests := make([]interface{}, len(columnTypes))
+	for i := range values {
+		dests[i] = &values[i]
+	}
+	for rows.Next() {
+		if err = rows.Scan(dests...); err != nil {
+			log.Error(err)
+			return nil, err
+		}
+		m := make(map[string]interface{}, len(columnTypes))
+		for i, ct := range columnTypes {
+			m[ct.Name()] = normalizeValue(values[i], ct.DatabaseTypeName())
+		}
+		l = append(l, m)
+	}
+	if err = rows.Err(); err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	return l, nil
+}
+
+// normalizeValue converts []byte into int64, uint64 or float64 for integer and floating point columns,
+// or string for other columns except binary columns. DECIMAL and NUMERIC are converted into string to keep precision
+func normalizeValue(v interface{}, dbType string) interface{} {
+	b, ok := v.([]byte)
+	if !ok {
+		return v
+	}
+
+	dbType = strings.ToUpper(dbType)
+	switch {
+	case strings.Contains(dbType, "BLOB"), strings.Contains(dbType, "BINARY"), dbType == "BYTEA":
+		return b
+	case strings.Contains(dbType, "INT") && !strings.Contains(dbType, "POINT") && !strings.Contains(dbType, "INTERVAL"):
+		if n, err := strconv.ParseInt(string(b), 10, 64); err == nil {
+			return n
+		}
+		if n, err := strconv.ParseUint(string(b), 10, 64); err == nil {
+			return n
+		}
+	case strings.Contains(dbType, "FLOAT"), strings.Contains(dbType, "DOUBLE"), dbType == "REAL":
+		if f, err := strconv.ParseFloat(string(b), 64); err == nil {
+			return f
+		}
+	}
+	return string(b)
+}
+
+// Pluck selects column of rows matching conditions into dest which is a pointer to slice, e.g. *[]string
+func (q *Query) Pluck(column string, dest interface{}) error {
+	return q.PluckContext(context.Background(), column, dest)
+}
+
+func (q *Query) PluckContext(ctx context.Context, column string, dest interface{}) error {
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Slice {
+		panic("must be a pointer to slice")
+	}
+
+	query, args := q.scoped(q.table.info()).selectQuery(q.table.dialect.QuoteIdent(column))
+	rows, err := q.table.query(ctx, query, args)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+	defer rows.Close()
+
+	sliceType := v.Elem().Type()
+	l := reflect.MakeSlice(sliceType, 0, 0)
+	for rows.Next() {
+		p := reflect.New(sliceType.Elem())
+		if err = rows.Scan(p.Interface()); err != nil {
+			log.Error(err)
+			return err
+		}
+		l = reflect.Append(l, p.Elem())
+	}
+	if err = rows.Err(); err != nil {
+		log.Error(err)
+		return err
+	}
+	v.Elem().Set(l)
+	return nil
+}
+
+func (t *Table) SelectMaps(where interface{}, args ...interface{}) ([]map[string]interface{}, error) {
+	return t.SelectMapsContext(context.Background(), where, args...)
+}
+
+func (t *Table) SelectMapsContext(ctx context.Context, where interface{}, args ...interface{}) ([]map[string]interface{}, error) {
+	return t.Where(where, args...).SelectMapsContext(ctx)
+}
+
+func (t *Table) Pluck(column string, dest interface{}, where interface{}, args ...interface{}) error {
+	return t.PluckContext(context.Background(), column, dest, where, args...)
+}
+
+func (t *Table) PluckContext(ctx context.Context, column string, dest interface{}, where interface{}, args ...interface{}) error {
+	return t.Where(where, args...).PluckContext(ctx, column, dest)
+}
+
+// Scalar runs raw query, and scans the single value into dest. It returns ErrNoRows if no row
+//
+//	var maxPrice float64
+//	err := db.Scalar(&maxPrice, "SELECT MAX(price) FROM products")
+func (s *session) Scalar(dest interface{}, query string, args ...interface{}) error {
+	return s.ScalarContext(context.Background(), dest, query, args...)
+}
+
+func (s *session) ScalarContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	query, args = s.bindRaw(query, args)
+	log.Debug(query, toReadableArgs(args))
+	err := s.exe.QueryRowContext(ctx, query, args...).Scan(dest)
+	if err != nil && err != sql.ErrNoRows {
+		log.Error(err)
+	}
+	return err
+}
@@ -0,0 +1,19 @@
+package sql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeValue(t *testing.T) {
+	require.Equal(t, int64(-3), normalizeValue([]byte("-3"), "BIGINT"))
+	require.Equal(t, uint64(18446744073709551615), normalizeValue([]byte("18446744073709551615"), "UNSIGNED BIGINT"))
+	require.Equal(t, int64(1), normalizeValue([]byte("1"), "int4"))
+	require.Equal(t, 1.5, normalizeValue([]byte("1.5"), "DOUBLE"))
+	require.Equal(t, "1.10", normalizeValue([]byte("1.10"), "DECIMAL"))
+	require.Equal(t, "apple", normalizeValue([]byte("apple"), "VARCHAR"))
+	require.Equal(t, []byte{1, 2}, normalizeValue([]byte{1, 2}, "BLOB"))
+	require.Equal(t, int64(1), normalizeValue(int64(1), "INTEGER"))
+	require.Nil(t, normalizeValue(nil, "TEXT"))
+}
@@ -0,0 +1,140 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"hash/fnv"
+	"time"
+
+	gosql "github.com/gopub/sql"
+)
+
+const lockName = "schema_migrations"
+
+// locker stops other instances from migrating at the same time. Lock waits until the lock is acquired or ctx is done
+type locker interface {
+	Lock(ctx context.Context) error
+	Unlock() error
+}
+
+func newLocker(db *gosql.DBWrapper) locker {
+	switch db.Dialect().Name() {
+	case gosql.MySQL.Name():
+		return &mysqlLocker{db: db.DB()}
+	case gosql.PostgreSQL.Name():
+		return &postgresLocker{db: db.DB()}
+	default:
+		return &tableLocker{db: db}
+	}
+}
+
+// mysqlLocker uses named lock which is held by a connection
+type mysqlLocker struct {
+	db   *sql.DB
+	conn *sql.Conn
+}
+
+func (l *mysqlLocker) Lock(ctx context.Context) error {
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	var ok sql.NullInt64
+	// Negative timeout means waiting infinitely
+	if err = conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, -1)", lockName).Scan(&ok); err != nil {
+		conn.Close()
+		return err
+	}
+	if ok.Int64 != 1 {
+		conn.Close()
+		return errors.New("cannot get lock " + lockName)
+	}
+	l.conn = conn
+	return nil
+}
+
+func (l *mysqlLocker) Unlock() error {
+	defer l.conn.Close()
+	_, err := l.conn.ExecContext(context.Background(), "SELECT RELEASE_LOCK(?)", lockName)
+	return err
+}
+
+// postgresLocker uses session level advisory lock which is held by a connection
+type postgresLocker struct {
+	db   *sql.DB
+	conn *sql.Conn
+}
+
+func (l *postgresLocker) key() int64 {
+	h := fnv.New64a()
+	h.Write([]byte(lockName))
+	return int64(h.Sum64())
+}
+
+func (l *postgresLocker) Lock(ctx context.Context) error {
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	if _, err = conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", l.key()); err != nil {
+		conn.Close()
+		return err
+	}
+	l.conn = conn
+	return nil
+}
+
+func (l *postgresLocker) Unlock() error {
+	defer l.conn.Close()
+	_, err := l.conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", l.key())
+	return err
+}
+
+type migrationLock struct {
+	ID       int `sql:"primary key"`
+	LockedAt int64
+}
+
+func (migrationLock) TableName() string {
+	return "schema_migrations_lock"
+}
+
+// tableLocker inserts a row as the lock, which is used by SQLite and other databases without named locks.
+// The row must be deleted manually if the process exits while holding the lock
+type tableLocker struct {
+	db *gosql.DBWrapper
+}
+
+func (l *tableLocker) Lock(ctx context.Context) error {
+	if err := l.db.CreateTableContext(ctx, &migrationLock{}, gosql.IfNotExists); err != nil {
+		return err
+	}
+	for {
+		ok, err := l.db.ExistsContext(ctx, &migrationLock{ID: 1})
+		if err != nil {
+			return err
+		}
+		if !ok {
+			err = l.db.InsertContext(ctx, &migrationLock{ID: 1, LockedAt: time.Now().Unix()})
+			if err == nil {
+				return nil
+			}
+
+			// Insert fails if the lock is taken by others just now, or for other reasons
+			if ok, _ = l.db.ExistsContext(ctx, &migrationLock{ID: 1}); !ok {
+				return err
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
+
+func (l *tableLocker) Unlock() error {
+	return l.db.Delete(&migrationLock{ID: 1})
+}
@@ -0,0 +1,290 @@
+// Package migrate applies versioned schema migrations, and records applied versions in table schema_migrations
+//
+//	m := migrate.New(db)
+//	m.Register(1, "create_users", createUsers, dropUsers)
+//	if err := m.LoadFS(migrations, "migrations"); err != nil {
+//		return err
+//	}
+//	err := m.Up()
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/gopub/log"
+	"github.com/gopub/sql"
+)
+
+// Func changes schema in transaction tx
+type Func func(tx *sql.TxWrapper) error
+
+type Migration struct {
+	Version int64
+	Name    string
+	Up      Func
+	Down    Func
+}
+
+// Status is state of a migration
+type Status struct {
+	Version int64
+	Name    string
+	Applied bool
+
+	// AppliedAt is unix time when the migration was applied
+	AppliedAt int64
+}
+
+type schemaMigration struct {
+	Version   int64  `sql:"primary key"`
+	Name      string `sql:"size=255"`
+	AppliedAt int64
+}
+
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// Migrator applies migrations to a database.
+// Each migration runs in a transaction. Note that MySQL commits DDL statements implicitly,
+// so a failed migration may be partially applied on MySQL
+type Migrator struct {
+	db         *sql.DBWrapper
+	migrations map[int64]*Migration
+}
+
+func New(db *sql.DBWrapper) *Migrator {
+	return &Migrator{
+		db:         db,
+		migrations: make(map[int64]*Migration),
+	}
+}
+
+// Register adds a migration. down can be nil if it can't be rolled back
+func (m *Migrator) Register(version int64, name string, up, down Func) {
+	if version <= 0 {
+		panic(fmt.Sprintf("invalid version: %d", version))
+	}
+	if up == nil {
+		panic("up is nil")
+	}
+	if _, ok := m.migrations[version]; ok {
+		panic(fmt.Sprintf("duplicate version: %d", version))
+	}
+	m.migrations[version] = &Migration{
+		Version: version,
+		Name:    name,
+		Up:      up,
+		Down:    down,
+	}
+}
+
+// Migrations returns registered migrations in order of version
+func (m *Migrator) Migrations() []*Migration {
+	l := make([]*Migration, 0, len(m.migrations))
+	for _, mg := range m.migrations {
+		l = append(l, mg)
+	}
+	sort.Slice(l, func(i, j int) bool {
+		return l[i].Version < l[j].Version
+	})
+	return l
+}
+
+// Up applies all pending migrations in order of version
+func (m *Migrator) Up() error {
+	return m.UpContext(context.Background())
+}
+
+func (m *Migrator) UpContext(ctx context.Context) error {
+	return m.withLock(ctx, func(applied map[int64]*schemaMigration) error {
+		for _, mg := range m.Migrations() {
+			if _, ok := applied[mg.Version]; ok {
+				continue
+			}
+			if err := m.run(ctx, mg, true); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Down rolls back the last n applied migrations
+func (m *Migrator) Down(n int) error {
+	return m.DownContext(context.Background(), n)
+}
+
+func (m *Migrator) DownContext(ctx context.Context, n int) error {
+	if n <= 0 {
+		return nil
+	}
+	return m.withLock(ctx, func(applied map[int64]*schemaMigration) error {
+		versions := make([]int64, 0, len(applied))
+		for v := range applied {
+			versions = append(versions, v)
+		}
+		sort.Slice(versions, func(i, j int) bool {
+			return versions[i] > versions[j]
+		})
+		if n > len(versions) {
+			n = len(versions)
+		}
+		for _, v := range versions[:n] {
+			mg, ok := m.migrations[v]
+			if !ok {
+				return fmt.Errorf("migration %d is not registered", v)
+			}
+			if mg.Down == nil {
+				return fmt.Errorf("migration %d_%s can't be rolled back", v, mg.Name)
+			}
+			if err := m.run(ctx, mg, false); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Status returns states of registered and applied migrations in order of version
+func (m *Migrator) Status() ([]*Status, error) {
+	return m.StatusContext(context.Background())
+}
+
+func (m *Migrator) StatusContext(ctx context.Context) ([]*Status, error) {
+	// Status doesn't change schema, so nothing is applied if the table doesn't exist
+	exists, err := m.tableExists(ctx)
+	if err != nil {
+		return nil, err
+	}
+	applied := make(map[int64]*schemaMigration)
+	if exists {
+		if applied, err = m.applied(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	l := make([]*Status, 0, len(m.migrations))
+	for _, mg := range m.Migrations() {
+		s := &Status{Version: mg.Version, Name: mg.Name}
+		if a, ok := applied[mg.Version]; ok {
+			s.Applied = true
+			s.AppliedAt = a.AppliedAt
+		}
+		l = append(l, s)
+	}
+
+	// Applied but not registered, e.g. applied by a newer release
+	for v, a := range applied {
+		if _, ok := m.migrations[v]; !ok {
+			l = append(l, &Status{Version: v, Name: a.Name, Applied: true, AppliedAt: a.AppliedAt})
+		}
+	}
+	sort.Slice(l, func(i, j int) bool {
+		return l[i].Version < l[j].Version
+	})
+	return l, nil
+}
+
+// withLock calls f with applied migrations while holding the migration lock
+func (m *Migrator) withLock(ctx context.Context, f func(applied map[int64]*schemaMigration) error) error {
+	l := newLocker(m.db)
+	if err := l.Lock(ctx); err != nil {
+		log.Error(err)
+		return err
+	}
+	defer func() {
+		if err := l.Unlock(); err != nil {
+			log.Error(err)
+		}
+	}()
+
+	// Table is created after locking, so that instances don't create it concurrently
+	if err := m.createTable(ctx); err != nil {
+		return err
+	}
+
+	// Read applied versions after locking, as another instance may have migrated
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return err
+	}
+	return f(applied)
+}
+
+func (m *Migrator) createTable(ctx context.Context) error {
+	return m.db.CreateTableContext(ctx, &schemaMigration{}, sql.IfNotExists)
+}
+
+// tableExists returns true if table schema_migrations exists. It's assumed to exist if dialect isn't an Inspector
+func (m *Migrator) tableExists(ctx context.Context) (bool, error) {
+	inspector, ok := m.db.Dialect().(sql.Inspector)
+	if !ok {
+		return true, nil
+	}
+	columns, err := inspector.Columns(ctx, m.db.DB(), schemaMigration{}.TableName())
+	if err != nil {
+		log.Error(err)
+		return false, err
+	}
+	return len(columns) > 0, nil
+}
+
+func (m *Migrator) applied(ctx context.Context) (map[int64]*schemaMigration, error) {
+	var l []*schemaMigration
+	if err := m.db.SelectContext(ctx, &l, ""); err != nil {
+		return nil, err
+	}
+	applied := make(map[int64]*schemaMigration, len(l))
+	for _, a := range l {
+		applied[a.Version] = a
+	}
+	return applied, nil
+}
+
+// run applies or rolls back mg in a transaction
+func (m *Migrator) run(ctx context.Context, mg *Migration, up bool) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	if up {
+		err = mg.Up(tx)
+		if err == nil {
+			err = tx.InsertContext(ctx, &schemaMigration{
+				Version:   mg.Version,
+				Name:      mg.Name,
+				AppliedAt: time.Now().Unix(),
+			})
+		}
+	} else {
+		err = mg.Down(tx)
+		if err == nil {
+			err = tx.DeleteContext(ctx, &schemaMigration{Version: mg.Version})
+		}
+	}
+
+	if err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil && !errors.Is(rbErr, sql.ErrTxDone) {
+			log.Error(rbErr)
+		}
+		return fmt.Errorf("migrate %d_%s: %w", mg.Version, mg.Name, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error(err)
+		return err
+	}
+	if up {
+		log.Infof("Applied migration %d_%s", mg.Version, mg.Name)
+	} else {
+		log.Infof("Rolled back migration %d_%s", mg.Version, mg.Name)
+	}
+	return nil
+}
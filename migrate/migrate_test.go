@@ -0,0 +1,44 @@
+package migrate
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gopub/sql"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrator(t *testing.T) {
+	db, err := sql.NewDBWrapper("sqlite3", ":memory:")
+	require.NoError(t, err)
+	db.DB().SetMaxOpenConns(1)
+	defer db.Close()
+
+	m := New(db)
+	m.Register(1, "create_users", func(tx *sql.TxWrapper) error {
+		_, err := tx.Exec("CREATE TABLE users(id INTEGER PRIMARY KEY)")
+		return err
+	}, func(tx *sql.TxWrapper) error {
+		_, err := tx.Exec("DROP TABLE users")
+		return err
+	})
+
+	l, err := m.Status()
+	require.NoError(t, err)
+	require.Len(t, l, 1)
+	require.False(t, l[0].Applied)
+	columns, err := sql.SQLite.(sql.Inspector).Columns(context.Background(), db.DB(), "schema_migrations")
+	require.NoError(t, err)
+	require.Empty(t, columns)
+
+	require.NoError(t, m.Up())
+	l, err = m.Status()
+	require.NoError(t, err)
+	require.True(t, l[0].Applied)
+
+	require.NoError(t, m.Down(1))
+	l, err = m.Status()
+	require.NoError(t, err)
+	require.False(t, l[0].Applied)
+}
@@ -0,0 +1,166 @@
+package migrate
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gopub/sql"
+)
+
+// e.g. 0001_create_users.up.sql
+var _fileNameRegexp = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// LoadFS registers migrations of sql files in dir of fsys.
+// Files are named as <version>_<name>.up.sql and <version>_<name>.down.sql, and down file is optional.
+// Statements in a file are separated by semicolons
+func (m *Migrator) LoadFS(fsys fs.FS, dir string) error {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return err
+	}
+
+	files := make(map[int64]*Migration)
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		matches := _fileNameRegexp.FindStringSubmatch(e.Name())
+		if matches == nil {
+			continue
+		}
+		version, err := strconv.ParseInt(matches[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("parse version of %s: %w", e.Name(), err)
+		}
+		data, err := fs.ReadFile(fsys, path.Join(dir, e.Name()))
+		if err != nil {
+			return err
+		}
+
+		mg := files[version]
+		if mg == nil {
+			mg = &Migration{Version: version, Name: matches[2]}
+			files[version] = mg
+		} else if mg.Name != matches[2] {
+			return fmt.Errorf("version %d has different names: %s and %s", version, mg.Name, matches[2])
+		}
+
+		f := execFunc(splitStatements(string(data)))
+		if matches[3] == "up" {
+			mg.Up = f
+		} else {
+			mg.Down = f
+		}
+	}
+
+	for v, mg := range files {
+		if mg.Up == nil {
+			return fmt.Errorf("no up file of version %d", v)
+		}
+	}
+	for _, mg := range files {
+		m.Register(mg.Version, mg.Name, mg.Up, mg.Down)
+	}
+	return nil
+}
+
+func execFunc(statements []string) Func {
+	return func(tx *sql.TxWrapper) error {
+		for _, s := range statements {
+			if _, err := tx.Exec(s); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// splitStatements splits sql by semicolons which are not in quotes or comments. Empty statements are dropped.
+// Line comments are removed, and block comments are kept as MySQL runs /*! ... */ comments.
+// Dollar quoted strings of PostgreSQL, e.g. $$ ... $$ or $body$ ... $body$, are not split
+func splitStatements(sql string) []string {
+	var statements []string
+	var b strings.Builder
+	add := func() {
+		if s := strings.TrimSpace(b.String()); len(s) > 0 {
+			statements = append(statements, s)
+		}
+		b.Reset()
+	}
+
+	var quote byte
+	for i := 0; i < len(sql); i++ {
+		c := sql[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '-' && i+1 < len(sql) && sql[i+1] == '-':
+			// Skip line comment
+			for i < len(sql) && sql[i] != '\n' {
+				i++
+			}
+			if i < len(sql) {
+				b.WriteByte('\n')
+			}
+			continue
+		case c == '/' && i+1 < len(sql) && sql[i+1] == '*':
+			end := strings.Index(sql[i+2:], "*/")
+			if end < 0 {
+				end = len(sql)
+			} else {
+				end += i + 4
+			}
+			b.WriteString(sql[i:end])
+			i = end - 1
+			continue
+		case c == '$':
+			if tag := dollarTag(sql, i); len(tag) > 0 {
+				end := strings.Index(sql[i+len(tag):], tag)
+				if end < 0 {
+					end = len(sql)
+				} else {
+					end += i + 2*len(tag)
+				}
+				b.WriteString(sql[i:end])
+				i = end - 1
+				continue
+			}
+		case c == ';':
+			add()
+			continue
+		}
+		b.WriteByte(c)
+	}
+	add()
+	return statements
+}
+
+// dollarTag returns tag of dollar quote which starts at i of sql, e.g. $$ or $body$
+func dollarTag(sql string, i int) string {
+	// $ can be part of an identifier, and $1 is a placeholder
+	if i > 0 && isIdentByte(sql[i-1]) {
+		return ""
+	}
+	for j := i + 1; j < len(sql); j++ {
+		c := sql[j]
+		if c == '$' {
+			return sql[i : j+1]
+		}
+		if !isIdentByte(c) || (j == i+1 && c >= '0' && c <= '9') {
+			return ""
+		}
+	}
+	return ""
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
@@ -0,0 +1,63 @@
+package migrate
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitStatements(t *testing.T) {
+	statements := splitStatements(`
+-- create users; with comment
+CREATE TABLE users(id BIGINT PRIMARY KEY, name VARCHAR(20) NOT NULL DEFAULT ';');
+INSERT INTO users(id, name) VALUES (1, 'a;b');;
+`)
+	require.Equal(t, []string{
+		"CREATE TABLE users(id BIGINT PRIMARY KEY, name VARCHAR(20) NOT NULL DEFAULT ';')",
+		"INSERT INTO users(id, name) VALUES (1, 'a;b')",
+	}, statements)
+}
+
+func TestSplitStatements_Postgres(t *testing.T) {
+	statements := splitStatements(`
+/* functions; triggers */
+CREATE FUNCTION touch() RETURNS trigger AS $$
+BEGIN
+	NEW.updated_at = now();
+	RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+CREATE FUNCTION f() RETURNS text AS $body$ SELECT '$$;' $body$ LANGUAGE sql;
+SELECT $1, a$b FROM t;
+`)
+	require.Equal(t, []string{
+		"/* functions; triggers */\nCREATE FUNCTION touch() RETURNS trigger AS $$\nBEGIN\n\tNEW.updated_at = now();\n\tRETURN NEW;\nEND;\n$$ LANGUAGE plpgsql",
+		"CREATE FUNCTION f() RETURNS text AS $body$ SELECT '$$;' $body$ LANGUAGE sql",
+		"SELECT $1, a$b FROM t",
+	}, statements)
+}
+
+func TestMigrator_LoadFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"migrations/0001_create_users.up.sql":   {Data: []byte("CREATE TABLE users(id BIGINT)")},
+		"migrations/0001_create_users.down.sql": {Data: []byte("DROP TABLE users")},
+		"migrations/0002_add_name.up.sql":       {Data: []byte("ALTER TABLE users ADD name TEXT")},
+		"migrations/README.md":                  {Data: []byte("doc")},
+		"migrations/0003_missing_up.down.sql":   {Data: []byte("")},
+	}
+
+	m := New(nil)
+	require.Error(t, m.LoadFS(fsys, "migrations"))
+
+	delete(fsys, "migrations/0003_missing_up.down.sql")
+	m = New(nil)
+	require.NoError(t, m.LoadFS(fsys, "migrations"))
+	l := m.Migrations()
+	require.Len(t, l, 2)
+	require.Equal(t, int64(1), l[0].Version)
+	require.Equal(t, "create_users", l[0].Name)
+	require.NotNil(t, l[0].Down)
+	require.Equal(t, "add_name", l[1].Name)
+	require.Nil(t, l[1].Down)
+}
@@ -0,0 +1,50 @@
+package sql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTable_AffectedRows(t *testing.T) {
+	type Item struct {
+		ID        int `sql:"primary key"`
+		Price     float64
+		DeletedAt int64 `sql:"deleted_at"`
+	}
+	db := openSQLite(t)
+	require.NoError(t, db.CreateTable(&Item{}))
+	for i := 1; i <= 3; i++ {
+		require.NoError(t, db.Insert(&Item{ID: i, Price: float64(i)}))
+	}
+
+	n, err := db.UpdateResult(&Item{ID: 1, Price: 10})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), n)
+	n, err = db.UpdateResult(&Item{ID: 9, Price: 10})
+	require.NoError(t, err)
+	require.Equal(t, int64(0), n)
+	require.NoError(t, db.Update(&Item{ID: 9}))
+	require.NoError(t, db.Delete(&Item{ID: 9}))
+
+	tbl := db.Table(&Item{})
+	n, err = tbl.Delete("price < ?", 3)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), n)
+	n, err = tbl.Restore("id = ?", 2)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), n)
+	n, err = tbl.HardDelete("id > ?", 1)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), n)
+
+	db.SetErrNotFound(true)
+	_, err = db.UpdateResult(&Item{ID: 9})
+	require.Equal(t, ErrNotFound, err)
+	require.Equal(t, ErrNotFound, db.Update(&Item{ID: 9}))
+	require.Equal(t, ErrNotFound, db.Delete(&Item{ID: 9}))
+	p := &Item{ID: 1}
+	require.NoError(t, db.Delete(p))
+	require.NotZero(t, p.DeletedAt)
+	require.Equal(t, ErrNotFound, db.Delete(&Item{ID: 1}))
+}
@@ -0,0 +1,291 @@
+package sql
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// RowValueComparer is implemented by dialects which support row value comparison, e.g. (a, b) > (?, ?).
+// Keyset conditions of other dialects are expanded into OR terms
+type RowValueComparer interface {
+	CompareRowValues() bool
+}
+
+func (d mysqlDialect) CompareRowValues() bool {
+	return true
+}
+
+// CompareRowValues returns true as row values are supported since SQLite 3.15.0
+func (d sqliteDialect) CompareRowValues() bool {
+	return true
+}
+
+func (d postgresDialect) CompareRowValues() bool {
+	return true
+}
+
+// Page is the result of Paginator.Page. Cursors are empty if there's no next or previous page
+type Page struct {
+	NextCursor string
+	PrevCursor string
+}
+
+type keysetOrder struct {
+	column string
+	desc   bool
+}
+
+// Paginator reads pages of records by keyset pagination, which seeks rows after the last row instead of skipping rows by offset.
+// Columns of orders must be NOT NULL, and end with the primary key
+//
+//	p := db.Table(&Product{}).Where("price > ?", 0).Paginate("price DESC", "id")
+//	page, err := p.Page(&products, cursor, 20)
+//	// page.NextCursor and page.PrevCursor are passed as cursor to read next or previous page
+type Paginator struct {
+	query  *Query
+	orders []*keysetOrder
+}
+
+// Paginate returns a paginator ordered by orders, e.g. "price DESC", "id"
+func (q *Query) Paginate(orders ...string) *Paginator {
+	if len(orders) == 0 {
+		panic("no order")
+	}
+	p := &Paginator{query: q}
+	for _, o := range orders {
+		fields := strings.Fields(o)
+		if len(fields) == 0 || len(fields) > 2 {
+			panic("invalid order: " + o)
+		}
+		ko := &keysetOrder{column: fields[0]}
+		if len(fields) == 2 {
+			switch strings.ToUpper(fields[1]) {
+			case "ASC":
+			case "DESC":
+				ko.desc = true
+			default:
+				panic("invalid order: " + o)
+			}
+		}
+		p.orders = append(p.orders, ko)
+	}
+	return p
+}
+
+func (t *Table) Paginate(orders ...string) *Paginator {
+	return t.newQuery().Paginate(orders...)
+}
+
+// Page reads at most size records after or before the row of cursor into records which is a pointer to slice.
+// Empty cursor means the first page
+func (p *Paginator) Page(records interface{}, cursor string, size int) (*Page, error) {
+	return p.PageContext(context.Background(), records, cursor, size)
+}
+
+func (p *Paginator) PageContext(ctx context.Context, records interface{}, cursor string, size int) (*Page, error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("invalid size %d", size)
+	}
+	v := reflect.ValueOf(records)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Slice {
+		panic("must be a pointer to slice")
+	}
+	elemType := v.Elem().Type().Elem()
+	if elemType.Kind() == reflect.Ptr {
+		elemType = elemType.Elem()
+	}
+	if elemType.Kind() != reflect.Struct {
+		panic("slice element must be a struct or pointer to struct")
+	}
+	info := getColumnInfo(elemType)
+	p.validate(info)
+
+	backward := false
+	q := *p.query
+	q.where = append([]Condition(nil), p.query.where...)
+	if len(cursor) > 0 {
+		c, err := decodeCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+		args, err := p.cursorArgs(elemType, info, c)
+		if err != nil {
+			return nil, err
+		}
+		backward = c.Prev
+		q.where = append(q.where, p.keysetCondition(q.table.dialect, args, backward))
+	}
+	q.orderBy = p.orderBy(q.table.dialect, backward)
+	q.limit = int64(size) + 1
+	q.offset = 0
+	// SelectContext appends records, so rows of the previous page are dropped
+	v.Elem().Set(reflect.MakeSlice(v.Elem().Type(), 0, size+1))
+	if err := q.SelectContext(ctx, records); err != nil {
+		return nil, err
+	}
+
+	l := v.Elem()
+	more := l.Len() > size
+	if more {
+		l = l.Slice(0, size)
+	}
+	if backward {
+		swap := reflect.Swapper(l.Interface())
+		for i, j := 0, l.Len()-1; i < j; i, j = i+1, j-1 {
+			swap(i, j)
+		}
+	}
+	v.Elem().Set(l)
+
+	page := new(Page)
+	if l.Len() == 0 {
+		return page, nil
+	}
+	var err error
+	if more || backward {
+		if page.NextCursor, err = p.encodeCursor(l.Index(l.Len()-1), info, false); err != nil {
+			return nil, err
+		}
+	}
+	if (more && backward) || (!backward && len(cursor) > 0) {
+		if page.PrevCursor, err = p.encodeCursor(l.Index(0), info, true); err != nil {
+			return nil, err
+		}
+	}
+	return page, nil
+}
+
+// validate panics if columns are not in info, or don't end with the primary key
+func (p *Paginator) validate(info *columnInfo) {
+	for _, o := range p.orders {
+		if _, ok := info.nameToIndex[o.column]; !ok {
+			panic("column not found: " + o.column)
+		}
+	}
+	n := len(info.pkNames)
+	if n == 0 || len(p.orders) < n {
+		panic("orders must end with primary key")
+	}
+	for _, o := range p.orders[len(p.orders)-n:] {
+		if IndexOfString(info.pkNames, o.column) < 0 {
+			panic("orders must end with primary key")
+		}
+	}
+}
+
+// orderBy returns ORDER BY terms. Directions are reversed for previous page
+func (p *Paginator) orderBy(d Dialect, backward bool) []string {
+	l := make([]string, len(p.orders))
+	for i, o := range p.orders {
+		l[i] = d.QuoteIdent(o.column)
+		if o.desc != backward {
+			l[i] += " DESC"
+		}
+	}
+	return l
+}
+
+// keysetCondition returns condition of rows after values in the order, or before values if backward is true.
+// Row value comparison is used if all columns are in the same direction, e.g. (a, b) > (?, ?),
+// otherwise it's expanded into OR terms, e.g. a > ? OR (a = ? AND b < ?)
+func (p *Paginator) keysetCondition(d Dialect, values []interface{}, backward bool) Condition {
+	ops := make([]string, len(p.orders))
+	sameOp := true
+	for i, o := range p.orders {
+		ops[i] = ">"
+		if o.desc != backward {
+			ops[i] = "<"
+		}
+		sameOp = sameOp && ops[i] == ops[0]
+	}
+
+	if rc, ok := d.(RowValueComparer); ok && rc.CompareRowValues() && sameOp && len(p.orders) > 1 {
+		columns := make([]string, len(p.orders))
+		for i, o := range p.orders {
+			columns[i] = o.column
+		}
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(values)), ", ")
+		return Expr(fmt.Sprintf("(%s) %s (%s)", quoteIdents(d, columns), ops[0], placeholders), values...)
+	}
+
+	var terms []string
+	var args []interface{}
+	for i, o := range p.orders {
+		var b strings.Builder
+		for j := 0; j < i; j++ {
+			b.WriteString(d.QuoteIdent(p.orders[j].column))
+			b.WriteString(" = ? AND ")
+			args = append(args, values[j])
+		}
+		b.WriteString(d.QuoteIdent(o.column) + " " + ops[i] + " ?")
+		args = append(args, values[i])
+		terms = append(terms, "("+b.String()+")")
+	}
+	return Expr(strings.Join(terms, " OR "), args...)
+}
+
+// cursor is encoded as base64url of json
+type cursor struct {
+	Prev   bool              `json:"p,omitempty"`
+	Values []json.RawMessage `json:"v"`
+}
+
+func decodeCursor(s string) (*cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	c := new(cursor)
+	if err = json.Unmarshal(data, c); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return c, nil
+}
+
+func (p *Paginator) encodeCursor(elem reflect.Value, info *columnInfo, prev bool) (string, error) {
+	for elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+	c := &cursor{Prev: prev, Values: make([]json.RawMessage, len(p.orders))}
+	for i, o := range p.orders {
+		data, err := json.Marshal(elem.FieldByIndex(info.nameToIndex[o.column]).Interface())
+		if err != nil {
+			return "", err
+		}
+		c.Values[i] = data
+	}
+	data, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// cursorArgs decodes values of cursor into fields of a new record, and returns them as statement arguments.
+// It returns ErrInvalidCursor if a value is null or doesn't match type of the field
+func (p *Paginator) cursorArgs(typ reflect.Type, info *columnInfo, c *cursor) ([]interface{}, error) {
+	if len(c.Values) != len(p.orders) {
+		return nil, ErrInvalidCursor
+	}
+	elem := newRecord(typ).Elem()
+	args := make([]interface{}, len(p.orders))
+	for i, o := range p.orders {
+		if string(c.Values[i]) == "null" {
+			return nil, ErrInvalidCursor
+		}
+		field := elem.FieldByIndex(info.nameToIndex[o.column])
+		if err := json.Unmarshal(c.Values[i], field.Addr().Interface()); err != nil {
+			return nil, ErrInvalidCursor
+		}
+		arg, err := getFieldValueByName(elem, info, o.column)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = arg
+	}
+	return args, nil
+}
@@ -0,0 +1,110 @@
+package sql
+
+import (
+	"encoding/base64"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaginator_keysetCondition(t *testing.T) {
+	tbl := &Table{dialect: MySQL, name: "products"}
+
+	t.Run("RowValue", func(t *testing.T) {
+		p := tbl.Paginate("price DESC", "id DESC")
+		query, args := p.keysetCondition(MySQL, []interface{}{0.2, 3}, false).ToSQL()
+		require.Equal(t, "(`price`, `id`) < (?, ?)", query)
+		require.Equal(t, []interface{}{0.2, 3}, args)
+
+		query, _ = p.keysetCondition(MySQL, []interface{}{0.2, 3}, true).ToSQL()
+		require.Equal(t, "(`price`, `id`) > (?, ?)", query)
+	})
+
+	t.Run("Mixed", func(t *testing.T) {
+		p := tbl.Paginate("price DESC", "id")
+		query, args := p.keysetCondition(MySQL, []interface{}{0.2, 3}, false).ToSQL()
+		require.Equal(t, "(`price` < ?) OR (`price` = ? AND `id` > ?)", query)
+		require.Equal(t, []interface{}{0.2, 0.2, 3}, args)
+		require.Equal(t, []string{"`price`", "`id` DESC"}, p.orderBy(MySQL, true))
+	})
+}
+
+func TestPaginator_cursor(t *testing.T) {
+	type Product struct {
+		ID    int64 `sql:"primary key"`
+		Price float64
+	}
+	info := getColumnInfo(reflect.TypeOf(Product{}))
+	p := (&Table{dialect: SQLite, name: "products"}).Paginate("price", "id")
+	s, err := p.encodeCursor(reflect.ValueOf(&Product{ID: 1 << 60, Price: 0.5}), info, true)
+	require.NoError(t, err)
+	c, err := decodeCursor(s)
+	require.NoError(t, err)
+	require.True(t, c.Prev)
+	args, err := p.cursorArgs(reflect.TypeOf(Product{}), info, c)
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{0.5, int64(1 << 60)}, args)
+
+	_, err = decodeCursor("not a cursor")
+	require.Equal(t, ErrInvalidCursor, err)
+
+	require.Panics(t, func() {
+		(&Table{dialect: SQLite, name: "products"}).Paginate("id", "price").validate(info)
+	})
+}
+
+func TestPaginator_Page(t *testing.T) {
+	type Product struct {
+		ID    int64 `sql:"primary key"`
+		Price float64
+	}
+	db := openSQLite(t)
+	require.NoError(t, db.CreateTable(&Product{}))
+	for i := 1; i <= 7; i++ {
+		require.NoError(t, db.Insert(&Product{ID: int64(i), Price: float64(i % 3)}))
+	}
+	ids := func(l []*Product) []int64 {
+		var res []int64
+		for _, p := range l {
+			res = append(res, p.ID)
+		}
+		return res
+	}
+
+	// price: 1 2 0 1 2 0 1 => order by price DESC, id: 2 5 1 4 7 3 6
+	p := db.Table("products").Paginate("price DESC", "id")
+	var products []*Product
+	page, err := p.Page(&products, "", 3)
+	require.NoError(t, err)
+	require.Equal(t, []int64{2, 5, 1}, ids(products))
+	require.Empty(t, page.PrevCursor)
+
+	page, err = p.Page(&products, page.NextCursor, 3)
+	require.NoError(t, err)
+	require.Equal(t, []int64{4, 7, 3}, ids(products))
+	require.NotEmpty(t, page.PrevCursor)
+	next := page.NextCursor
+
+	page, err = p.Page(&products, page.PrevCursor, 3)
+	require.NoError(t, err)
+	require.Equal(t, []int64{2, 5, 1}, ids(products))
+	require.Empty(t, page.PrevCursor)
+
+	page, err = p.Page(&products, next, 3)
+	require.NoError(t, err)
+	require.Equal(t, []int64{6}, ids(products))
+	require.Empty(t, page.NextCursor)
+
+	page, err = p.Page(&products, page.PrevCursor, 3)
+	require.NoError(t, err)
+	require.Equal(t, []int64{4, 7, 3}, ids(products))
+
+	_, err = p.Page(&products, "", 0)
+	require.Error(t, err)
+
+	for _, v := range []string{`{"v":[null,1]}`, `{"v":["a",1]}`, `{"v":[1]}`} {
+		_, err = p.Page(&products, base64.RawURLEncoding.EncodeToString([]byte(v)), 3)
+		require.Equal(t, ErrInvalidCursor, err, v)
+	}
+}
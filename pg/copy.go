@@ -0,0 +1,79 @@
+package pg
+
+import (
+	"context"
+	"strings"
+
+	"github.com/gopub/log"
+	"github.com/gopub/sql"
+)
+
+const copySavepoint = "pg_copy_from"
+
+// CopyFrom loads records into table with COPY ... FROM STDIN, and returns number of copied rows.
+// Columns and values are mapped in the same way as Table.Insert. Generated ids are not written back.
+// Copy is wrapped in a savepoint, so tx is still usable and nothing is loaded if it fails.
+// It requires driver github.com/lib/pq which supports COPY in prepared statements
+func CopyFrom(tx *sql.Tx, table string, records interface{}) (int64, error) {
+	return CopyFromContext(context.Background(), tx, table, records)
+}
+
+func CopyFromContext(ctx context.Context, tx *sql.Tx, table string, records interface{}) (int64, error) {
+	columns, rows, err := sql.InsertValues(records)
+	if err != nil {
+		log.Error(err)
+		return 0, err
+	}
+	if len(rows) == 0 {
+		return 0, nil
+	}
+
+	if _, err = tx.ExecContext(ctx, "SAVEPOINT "+copySavepoint); err != nil {
+		log.Error(err)
+		return 0, err
+	}
+
+	n, err := copyRows(ctx, tx, table, columns, rows)
+	if err != nil {
+		log.Error(err)
+		// ctx may be done, rollback with a fresh one
+		if _, rbErr := tx.ExecContext(context.Background(), "ROLLBACK TO SAVEPOINT "+copySavepoint); rbErr != nil {
+			log.Error(rbErr)
+		}
+		return 0, err
+	}
+
+	if _, err = tx.ExecContext(ctx, "RELEASE SAVEPOINT "+copySavepoint); err != nil {
+		log.Error(err)
+		return 0, err
+	}
+	return n, nil
+}
+
+func copyRows(ctx context.Context, tx *sql.Tx, table string, columns []string, rows [][]interface{}) (int64, error) {
+	quoted := make([]string, len(columns))
+	for i, c := range columns {
+		quoted[i] = sql.PostgreSQL.QuoteIdent(c)
+	}
+	query := "COPY " + sql.PostgreSQL.QuoteIdent(table) + " (" + strings.Join(quoted, ", ") + ") FROM STDIN"
+	log.Debug(query)
+
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	for _, row := range rows {
+		if _, err = stmt.ExecContext(ctx, row...); err != nil {
+			return 0, err
+		}
+	}
+
+	// Exec without args flushes buffered rows and ends COPY
+	result, err := stmt.ExecContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
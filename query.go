@@ -0,0 +1,331 @@
+package sql
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/gopub/log"
+)
+
+// Query builds conditions and clauses of statements on a table
+// e.g. db.Table(&Product{}).Where("price < ?", 0.2).OrderBy("updated_at DESC").Limit(20).Offset(40).Select(&products)
+type Query struct {
+	table   *Table
+	where   []Condition
+	groupBy []string
+	orderBy []string
+	limit   int64
+	offset  int64
+
+	//include soft deleted rows
+	unscoped bool
+}
+
+// Where adds condition. where can be a string with args or a Condition. Multiple conditions are joined by AND
+func (q *Query) Where(where interface{}, args ...interface{}) *Query {
+	if c := toCondition(where, args); c != nil {
+		q.where = append(q.where, c)
+	}
+	return q
+}
+
+// OrderBy adds ORDER BY terms, e.g. "updated_at DESC"
+func (q *Query) OrderBy(orders ...string) *Query {
+	q.orderBy = append(q.orderBy, orders...)
+	return q
+}
+
+// GroupBy adds GROUP BY terms
+func (q *Query) GroupBy(columns ...string) *Query {
+	q.groupBy = append(q.groupBy, columns...)
+	return q
+}
+
+func (q *Query) Limit(limit int64) *Query {
+	q.limit = limit
+	return q
+}
+
+func (q *Query) Offset(offset int64) *Query {
+	q.offset = offset
+	return q
+}
+
+func (q *Query) writeWhere(buf *bytes.Buffer) []interface{} {
+	if len(q.where) == 0 {
+		return nil
+	}
+	var s string
+	var args []interface{}
+	if len(q.where) == 1 {
+		s, args = q.where[0].ToSQL()
+	} else {
+		s, args = And(q.where...).ToSQL()
+	}
+	buf.WriteString(" WHERE ")
+	buf.WriteString(s)
+	return args
+}
+
+func (q *Query) writeClauses(buf *bytes.Buffer) []interface{} {
+	args := q.writeWhere(buf)
+	if len(q.groupBy) > 0 {
+		buf.WriteString(" GROUP BY ")
+		buf.WriteString(strings.Join(q.groupBy, ", "))
+	}
+	if len(q.orderBy) > 0 {
+		buf.WriteString(" ORDER BY ")
+		buf.WriteString(strings.Join(q.orderBy, ", "))
+	}
+	if lo := q.table.dialect.LimitOffset(q.limit, q.offset); len(lo) > 0 {
+		buf.WriteString(" ")
+		buf.WriteString(lo)
+	}
+	return args
+}
+
+// selectQuery returns SELECT statement of columns and its arguments
+func (q *Query) selectQuery(columns string) (string, []interface{}) {
+	var buf bytes.Buffer
+	buf.WriteString("SELECT ")
+	buf.WriteString(columns)
+	buf.WriteString(" FROM ")
+	buf.WriteString(q.table.dialect.QuoteIdent(q.table.name))
+	args := q.writeClauses(&buf)
+	return buf.String(), args
+}
+
+func (q *Query) Select(records interface{}) error {
+	return q.SelectContext(context.Background(), records)
+}
+
+func (q *Query) SelectContext(ctx context.Context, records interface{}) error {
+	v := reflect.ValueOf(records)
+	if v.Kind() != reflect.Ptr {
+		panic("must be a pointer to slice")
+	}
+
+	if v.IsNil() && !v.CanSet() {
+		panic("cannot be set value")
+	}
+
+	sliceType := v.Type().Elem()
+	if sliceType.Kind() != reflect.Slice {
+		panic("must be a pointer to slice")
+	}
+
+	isPtr := false
+	elemType := sliceType.Elem()
+	if elemType.Kind() == reflect.Ptr {
+		elemType = elemType.Elem()
+		isPtr = true
+	}
+
+	if elemType.Kind() != reflect.Struct {
+		panic("slice element must be a struct or pointer to struct")
+	}
+
+	info := getColumnInfo(elemType)
+	query, args := q.scoped(info).selectQuery(quoteIdents(q.table.dialect, info.names))
+	rows, err := q.table.query(ctx, query, args)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+	defer rows.Close()
+
+	if v.IsNil() {
+		v.Set(reflect.New(sliceType))
+	}
+	sliceValue := v.Elem()
+	for rows.Next() {
+		ptrToElem := newRecord(elemType)
+		elem := ptrToElem.Elem()
+		err = rows.Scan(scanDests(elem, info)...)
+		if err != nil {
+			log.Error(err)
+			return err
+		}
+		track(elem, info, info.names)
+
+		if isPtr {
+			sliceValue = reflect.Append(sliceValue, ptrToElem)
+		} else {
+			sliceValue = reflect.Append(sliceValue, elem)
+		}
+	}
+	if err = rows.Err(); err != nil {
+		log.Error(err)
+		return err
+	}
+	v.Elem().Set(sliceValue)
+	return nil
+}
+
+func (q *Query) SelectOne(record interface{}) error {
+	return q.SelectOneContext(context.Background(), record)
+}
+
+func (q *Query) SelectOneContext(ctx context.Context, record interface{}) error {
+	rv := reflect.ValueOf(record)
+	if rv.Kind() != reflect.Ptr {
+		panic("not pointer to a struct")
+	}
+
+	//Store result in ev. If failed, don't change record's value
+	ev := newRecord(rv.Elem().Type()).Elem()
+	elem := ev
+	if elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+
+	if elem.Kind() != reflect.Struct {
+		panic("not pointer to a struct")
+	}
+
+	info := getColumnInfo(elem.Type())
+	query, args := q.scoped(info).selectQuery(quoteIdents(q.table.dialect, info.names))
+	err := q.table.queryRow(ctx, query, args).Scan(scanDests(elem, info)...)
+	if err != nil {
+		if err != ErrNoRows {
+			log.Error(err)
+		}
+		return err
+	}
+	track(elem, info, info.names)
+	rv.Elem().Set(ev)
+	return nil
+}
+
+// Count returns number of rows. If GroupBy is set, it returns number of groups
+func (q *Query) Count() (int, error) {
+	return q.CountContext(context.Background())
+}
+
+func (q *Query) CountContext(ctx context.Context) (int, error) {
+	var query string
+	var args []interface{}
+	sq := q.scoped(q.table.info())
+	if len(q.groupBy) > 0 {
+		query, args = sq.selectQuery("1")
+		query = "SELECT COUNT(*) FROM (" + query + ") AS t"
+	} else {
+		query, args = sq.selectQuery("COUNT(*)")
+	}
+
+	var count int
+	err := q.table.queryRow(ctx, query, args).Scan(&count)
+	if err != nil {
+		log.Error(err)
+		return 0, err
+	}
+	return count, nil
+}
+
+// Update sets columns to values in m on rows matching conditions, and returns number of affected rows.
+// GroupBy, OrderBy, Limit and Offset are not allowed
+func (q *Query) Update(m map[string]interface{}) (int64, error) {
+	return q.UpdateContext(context.Background(), m)
+}
+
+func (q *Query) UpdateContext(ctx context.Context, m map[string]interface{}) (int64, error) {
+	if len(m) == 0 {
+		panic("no column to update")
+	}
+
+	if len(q.where) == 0 {
+		panic("where is empty")
+	}
+
+	if len(q.groupBy) > 0 || len(q.orderBy) > 0 || q.limit > 0 || q.offset > 0 {
+		err := errors.New("update doesn't support GROUP BY, ORDER BY, LIMIT or OFFSET")
+		log.Error(err)
+		return 0, err
+	}
+
+	info := q.table.info()
+	if info != nil && len(info.updatedName) > 0 {
+		if _, ok := m[info.updatedName]; !ok {
+			// Copy to keep m unchanged
+			mu := make(map[string]interface{}, len(m)+1)
+			for k, v := range m {
+				mu[k] = v
+			}
+			mu[info.updatedName] = timestampValue(q.table.recordType().FieldByIndex(info.nameToIndex[info.updatedName]).Type, q.table.now()).Interface()
+			m = mu
+		}
+	}
+	query, args := q.scoped(info).updateQuery(m)
+	result, err := q.table.exec(ctx, query, args)
+	if err != nil {
+		log.Error(err)
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// updateQuery returns UPDATE statement and its arguments. Columns are sorted to make statement stable
+func (q *Query) updateQuery(m map[string]interface{}) (string, []interface{}) {
+	columns := make([]string, 0, len(m))
+	for c := range m {
+		columns = append(columns, c)
+	}
+	sort.Strings(columns)
+
+	var buf bytes.Buffer
+	buf.WriteString("UPDATE ")
+	buf.WriteString(q.table.dialect.QuoteIdent(q.table.name))
+	buf.WriteString(" SET ")
+	args := make([]interface{}, 0, len(m))
+	for i, c := range columns {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(q.table.dialect.QuoteIdent(c))
+		buf.WriteString(" = ?")
+		args = append(args, m[c])
+	}
+	args = append(args, q.writeWhere(&buf)...)
+	return buf.String(), args
+}
+
+// Delete deletes rows matching conditions. GroupBy, OrderBy, Limit and Offset are not allowed.
+// If table is created by a record with soft delete column, rows are marked as deleted unless query is Unscoped
+func (q *Query) Delete() (int64, error) {
+	return q.DeleteContext(context.Background())
+}
+
+func (q *Query) DeleteContext(ctx context.Context) (int64, error) {
+	if len(q.where) == 0 {
+		panic("where is empty")
+	}
+
+	if len(q.groupBy) > 0 || len(q.orderBy) > 0 || q.limit > 0 || q.offset > 0 {
+		err := errors.New("delete doesn't support GROUP BY, ORDER BY, LIMIT or OFFSET")
+		log.Error(err)
+		return 0, err
+	}
+
+	var query string
+	var args []interface{}
+	if info := q.table.info(); !q.unscoped && info != nil && len(info.deletedName) > 0 {
+		deletedAt := deletedAtValue(info, q.table.recordType(), q.table.now())
+		query, args = q.scoped(info).updateQuery(map[string]interface{}{info.deletedName: deletedAt})
+	} else {
+		var buf bytes.Buffer
+		buf.WriteString("DELETE FROM ")
+		buf.WriteString(q.table.dialect.QuoteIdent(q.table.name))
+		args = q.writeWhere(&buf)
+		query = buf.String()
+	}
+	result, err := q.table.exec(ctx, query, args)
+	if err != nil {
+		log.Error(err)
+		return 0, err
+	}
+	return result.RowsAffected()
+}
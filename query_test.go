@@ -0,0 +1,87 @@
+package sql
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuery_selectQuery(t *testing.T) {
+	t.Run("MySQL", func(t *testing.T) {
+		tbl := &Table{dialect: MySQL, name: "products"}
+		q := tbl.Where("price < ?", 0.2).OrderBy("updated_at DESC").Limit(20).Offset(40)
+		query, args := q.selectQuery("*")
+		require.Equal(t, "SELECT * FROM `products` WHERE price < ? ORDER BY updated_at DESC LIMIT 20 OFFSET 40", query)
+		require.Equal(t, []interface{}{0.2}, args)
+	})
+
+	t.Run("PostgreSQL", func(t *testing.T) {
+		tbl := &Table{dialect: PostgreSQL, name: "products"}
+		q := tbl.Where("price < ?", 0.2).Where("name = ?", "apple").GroupBy("name").Limit(10)
+		query, args := q.selectQuery("name")
+		require.Equal(t, `SELECT name FROM "products" WHERE (price < $1) AND (name = $2) GROUP BY name LIMIT 10`,
+			bindVars(tbl.dialect, query))
+		require.Equal(t, []interface{}{0.2, "apple"}, args)
+	})
+
+	t.Run("Condition", func(t *testing.T) {
+		tbl := &Table{dialect: PostgreSQL, name: "products"}
+		q := tbl.Where(In("id", []int{1, 2})).Where("name IN (?)", []string{"a", "b"})
+		query, args := q.selectQuery("id")
+		require.Equal(t, `SELECT id FROM "products" WHERE (id IN ($1, $2)) AND (name IN ($3, $4))`,
+			bindVars(tbl.dialect, query))
+		require.Equal(t, []interface{}{1, 2, "a", "b"}, args)
+	})
+
+	t.Run("EmptyWhere", func(t *testing.T) {
+		tbl := &Table{dialect: SQLite, name: "products"}
+		query, args := tbl.Where("").selectQuery("COUNT(*)")
+		require.Equal(t, `SELECT COUNT(*) FROM "products"`, query)
+		require.Empty(t, args)
+	})
+}
+
+func TestBindVars(t *testing.T) {
+	require.Equal(t, "a = ? AND b = ?", bindVars(MySQL, "a = ? AND b = ?"))
+	require.Equal(t, "a = $1 AND b = '?' AND c = $2", bindVars(PostgreSQL, "a = ? AND b = '?' AND c = ?"))
+}
+
+func TestQuery_updateQuery(t *testing.T) {
+	tbl := &Table{dialect: PostgreSQL, name: "products"}
+	query, args := tbl.Where(In("id", []int{1, 2})).updateQuery(map[string]interface{}{"price": 0.1, "name": "apple"})
+	require.Equal(t, `UPDATE "products" SET "name" = $1, "price" = $2 WHERE id IN ($3, $4)`, bindVars(tbl.dialect, query))
+	require.Equal(t, []interface{}{"apple", 0.1, 1, 2}, args)
+}
+
+func TestQuery_scoped(t *testing.T) {
+	type SoftProduct struct {
+		ID        int `sql:"primary key"`
+		Name      string
+		DeletedAt int64 `sql:"deleted_at"`
+	}
+	tbl := &Table{dialect: MySQL, name: "products", typ: reflect.TypeOf(SoftProduct{})}
+	info := tbl.info()
+	require.Equal(t, "deleted_at", info.deletedName)
+
+	query, args := tbl.Where("name = ?", "apple").scoped(info).selectQuery("*")
+	require.Equal(t, "SELECT * FROM `products` WHERE (name = ?) AND (`deleted_at` IS NULL)", query)
+	require.Equal(t, []interface{}{"apple"}, args)
+
+	query, _ = tbl.Where("name = ?", "apple").Unscoped().scoped(info).selectQuery("*")
+	require.Equal(t, "SELECT * FROM `products` WHERE name = ?", query)
+}
+
+func TestTable_pkQuery(t *testing.T) {
+	type Member struct {
+		GroupID int64 `sql:"primary key"`
+		UserID  int64 `sql:"primary key"`
+		Name    string
+	}
+	tbl := &Table{dialect: PostgreSQL, name: "members"}
+	typ := reflect.TypeOf(Member{})
+	info := getColumnInfo(typ)
+	query, args := tbl.pkQuery(typ, info, pkValues(reflect.ValueOf(Member{GroupID: 1, UserID: 2}), info)).selectQuery("1")
+	require.Equal(t, `SELECT 1 FROM "members" WHERE ("group_id" = $1) AND ("user_id" = $2)`, bindVars(tbl.dialect, query))
+	require.Equal(t, []interface{}{int64(1), int64(2)}, args)
+}
@@ -0,0 +1,101 @@
+package sql
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/gopub/log"
+)
+
+// Get selects record by primary key values which are in the order of primary key columns
+// e.g. db.Get(&product, 1)
+func (t *Table) Get(record interface{}, pk ...interface{}) error {
+	return t.GetContext(context.Background(), record, pk...)
+}
+
+func (t *Table) GetContext(ctx context.Context, record interface{}, pk ...interface{}) error {
+	typ := structType(record)
+	info := getColumnInfo(typ)
+	if len(info.pkNames) == 0 {
+		panic("no primary key")
+	}
+	if len(pk) != len(info.pkNames) {
+		panic(fmt.Sprintf("expect %d primary key values, got %d", len(info.pkNames), len(pk)))
+	}
+	return t.pkQuery(typ, info, pk).SelectOneContext(ctx, record)
+}
+
+// Exists returns true if a row with the primary key of record exists
+func (t *Table) Exists(record interface{}) (bool, error) {
+	return t.ExistsContext(context.Background(), record)
+}
+
+func (t *Table) ExistsContext(ctx context.Context, record interface{}) (bool, error) {
+	v := getStructValue(record)
+	info := getColumnInfo(v.Type())
+	if len(info.pkNames) == 0 {
+		panic("no primary key")
+	}
+
+	q := t.pkQuery(v.Type(), info, pkValues(v, info)).Limit(1)
+	query, args := q.scoped(info).selectQuery("1")
+	var one int
+	err := t.queryRow(ctx, query, args).Scan(&one)
+	if err == ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		log.Error(err)
+		return false, err
+	}
+	return true, nil
+}
+
+// Reload selects record again by its primary key
+func (t *Table) Reload(record interface{}) error {
+	return t.ReloadContext(context.Background(), record)
+}
+
+func (t *Table) ReloadContext(ctx context.Context, record interface{}) error {
+	v := getStructValue(record)
+	info := getColumnInfo(v.Type())
+	if len(info.pkNames) == 0 {
+		panic("no primary key")
+	}
+	return t.pkQuery(v.Type(), info, pkValues(v, info)).SelectOneContext(ctx, record)
+}
+
+// pkQuery returns query which matches primary key values of records of typ
+func (t *Table) pkQuery(typ reflect.Type, info *columnInfo, values []interface{}) *Query {
+	rt := *t
+	rt.typ = typ
+	q := rt.newQuery()
+	for i, name := range info.pkNames {
+		q.Where(Eq(t.dialect.QuoteIdent(name), values[i]))
+	}
+	return q
+}
+
+func pkValues(v reflect.Value, info *columnInfo) []interface{} {
+	values := make([]interface{}, len(info.pkNames))
+	for i, name := range info.pkNames {
+		values[i] = columnValue(v, info, name)
+	}
+	return values
+}
+
+// structType returns struct type of record which is a struct or pointers to struct
+func structType(record interface{}) reflect.Type {
+	typ := reflect.TypeOf(record)
+	if typ == nil {
+		panic("invalid")
+	}
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		panic("not struct: " + typ.Kind().String())
+	}
+	return typ
+}
@@ -0,0 +1,173 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/gopub/log"
+)
+
+// ScanRows scans rows into dest by column names, which works with joins, views and aggregate queries.
+// dest is a pointer to slice of structs, or a pointer to struct which is filled by the first row and gets ErrNoRows if no row.
+// Columns without fields are ignored. rows is not closed
+func ScanRows(rows *sql.Rows, dest interface{}) error {
+	return scanRows(rows, dest, false)
+}
+
+// ScanRowsStrict is the same as ScanRows, but returns an error if a column has no field
+func ScanRowsStrict(rows *sql.Rows, dest interface{}) error {
+	return scanRows(rows, dest, true)
+}
+
+func scanRows(rows *sql.Rows, dest interface{}, strict bool) error {
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		panic("dest must be a pointer to slice or struct")
+	}
+
+	typ := v.Elem().Type()
+	isSlice := typ.Kind() == reflect.Slice
+	elemType := typ
+	if isSlice {
+		elemType = typ.Elem()
+	}
+	structType := elemType
+	if structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+	if structType.Kind() != reflect.Struct {
+		panic("dest must be a pointer to slice or struct")
+	}
+
+	info := getColumnInfo(structType)
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	names, err := mapColumns(info, columns, strict)
+	if err != nil {
+		return err
+	}
+	var mappedNames []string
+	for _, name := range names {
+		if len(name) > 0 {
+			mappedNames = append(mappedNames, name)
+		}
+	}
+
+	var l reflect.Value
+	if isSlice {
+		l = reflect.MakeSlice(typ, 0, 0)
+	} else {
+		l = reflect.MakeSlice(reflect.SliceOf(typ), 0, 1)
+	}
+	dests := make([]interface{}, len(columns))
+	for rows.Next() {
+		ev := newRecord(elemType).Elem()
+		elem := ev
+		if elem.Kind() == reflect.Ptr {
+			elem = elem.Elem()
+		}
+		for i, name := range names {
+			if len(name) == 0 {
+				dests[i] = new(interface{})
+			} else {
+				dests[i] = scanDest(elem, info, name)
+			}
+		}
+		if err = rows.Scan(dests...); err != nil {
+			return err
+		}
+		track(elem, info, mappedNames)
+		l = reflect.Append(l, ev)
+		if !isSlice {
+			break
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	if isSlice {
+		v.Elem().Set(l)
+		return nil
+	}
+	if l.Len() == 0 {
+		return ErrNoRows
+	}
+	v.Elem().Set(l.Index(0))
+	return nil
+}
+
+// mapColumns returns column names of info in order of columns. Name is empty if the column has no field.
+// Column names are matched case insensitively
+func mapColumns(info *columnInfo, columns []string, strict bool) ([]string, error) {
+	names := make([]string, len(columns))
+	for i, c := range columns {
+		if _, ok := info.nameToIndex[c]; ok {
+			names[i] = c
+			continue
+		}
+		if lc := strings.ToLower(c); lc != c {
+			if _, ok := info.nameToIndex[lc]; ok {
+				names[i] = lc
+				continue
+			}
+		}
+		if strict {
+			return nil, fmt.Errorf("no field for column %s", c)
+		}
+	}
+	return names, nil
+}
+
+// Query runs raw query, and scans rows into dest which is a pointer to slice of structs. See ScanRows.
+// Like where clauses, slice arguments are expanded and ? is replaced with placeholders of the dialect
+func (s *session) Query(dest interface{}, query string, args ...interface{}) error {
+	return s.QueryContext(context.Background(), dest, query, args...)
+}
+
+func (s *session) QueryContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	query, args = s.bindRaw(query, args)
+	log.Debug(query, toReadableArgs(args))
+	rows, err := s.exe.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+	defer rows.Close()
+	if err = ScanRows(rows, dest); err != nil {
+		log.Error(err)
+		return err
+	}
+	return nil
+}
+
+// QueryOne runs raw query, and scans the first row into dest which is a pointer to struct. It returns ErrNoRows if no row
+func (s *session) QueryOne(dest interface{}, query string, args ...interface{}) error {
+	return s.QueryOneContext(context.Background(), dest, query, args...)
+}
+
+func (s *session) QueryOneContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	if k := reflect.TypeOf(dest); k == nil || k.Kind() != reflect.Ptr || k.Elem().Kind() == reflect.Slice {
+		panic("dest must be a pointer to struct")
+	}
+	query, args = s.bindRaw(query, args)
+	log.Debug(query, toReadableArgs(args))
+	rows, err := s.exe.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+	defer rows.Close()
+	if err = ScanRows(rows, dest); err != nil {
+		if err != ErrNoRows {
+			log.Error(err)
+		}
+		return err
+	}
+	return nil
+}
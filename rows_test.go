@@ -0,0 +1,66 @@
+package sql
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMapColumns(t *testing.T) {
+	type Stat struct {
+		Name  string
+		Total float64 `sql:"sum_price"`
+	}
+	info := getColumnInfo(reflect.TypeOf(Stat{}))
+	names, err := mapColumns(info, []string{"name", "SUM_PRICE", "extra"}, false)
+	require.NoError(t, err)
+	require.Equal(t, []string{"name", "sum_price", ""}, names)
+
+	_, err = mapColumns(info, []string{"name", "extra"}, true)
+	require.Error(t, err)
+}
+
+func TestSession_Query(t *testing.T) {
+	type Product struct {
+		ID    int `sql:"primary key"`
+		Name  string
+		Price float64
+	}
+	type Stat struct {
+		Name  string
+		Total float64
+	}
+	db := openSQLite(t)
+	require.NoError(t, db.CreateTable(&Product{}))
+	for i, name := range []string{"apple", "pear", "apple"} {
+		require.NoError(t, db.Insert(&Product{ID: i + 1, Name: name, Price: float64(i + 1)}))
+	}
+
+	var stats []*Stat
+	err := db.Query(&stats, "SELECT name, SUM(price) AS total FROM products WHERE id IN (?) GROUP BY name ORDER BY name", []int{1, 2, 3})
+	require.NoError(t, err)
+	require.Equal(t, []*Stat{{Name: "apple", Total: 4}, {Name: "pear", Total: 2}}, stats)
+
+	var stat Stat
+	err = db.QueryOne(&stat, "SELECT name, price AS total FROM products WHERE id NOT IN (?) AND name = ?", []int{}, "pear")
+	require.NoError(t, err)
+	require.Equal(t, Stat{Name: "pear", Total: 2}, stat)
+
+	var n int
+	require.NoError(t, db.Scalar(&n, "SELECT COUNT(*) FROM products WHERE name IN (?)", []string{"pear", "apple"}))
+	require.Equal(t, 3, n)
+
+	result, err := db.Exec("UPDATE products SET price = ? WHERE id IN (?)", 5.0, []int{1, 3})
+	require.NoError(t, err)
+	affected, err := result.RowsAffected()
+	require.NoError(t, err)
+	require.Equal(t, int64(2), affected)
+
+	s := &session{dialect: PostgreSQL}
+	query, args := s.bindRaw("SELECT * FROM products WHERE id IN (?) AND name = '?' AND price > ?", []interface{}{[]int{1, 2}, 0.5})
+	require.Equal(t, "SELECT * FROM products WHERE id IN ($1, $2) AND name = '?' AND price > $3", query)
+	require.Equal(t, []interface{}{1, 2, 0.5}, args)
+	query, _ = s.bindRaw("SELECT data ? 'k' FROM products", nil)
+	require.Equal(t, "SELECT data ? 'k' FROM products", query)
+}
@@ -0,0 +1,26 @@
+package sql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTable_Save(t *testing.T) {
+	t.Run("UntaggedCreatedAt", func(t *testing.T) {
+		type Product struct {
+			ID        int `sql:"primary key"`
+			Name      string
+			CreatedAt int64
+		}
+		db := openSQLite(t)
+		require.NoError(t, db.CreateTable(&Product{}))
+		require.NoError(t, db.Save(&Product{ID: 1, Name: "apple", CreatedAt: 100}))
+		require.NoError(t, db.Save(&Product{ID: 1, Name: "pear", CreatedAt: 200}))
+
+		var p Product
+		require.NoError(t, db.Get(&p, 1))
+		require.Equal(t, "pear", p.Name)
+		require.Equal(t, int64(100), p.CreatedAt)
+	})
+}
@@ -0,0 +1,165 @@
+package sql
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+)
+
+var _scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+
+// nullableHolder scans nullable column into field. NULL is converted into zero value
+type nullableHolder struct {
+	v reflect.Value
+}
+
+var _ sql.Scanner = (*nullableHolder)(nil)
+
+func (h *nullableHolder) Scan(src interface{}) error {
+	if src == nil {
+		h.v.Set(reflect.Zero(h.v.Type()))
+		return nil
+	}
+
+	switch h.v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var n sql.NullInt64
+		if err := n.Scan(src); err != nil {
+			return err
+		}
+		h.v.SetInt(n.Int64)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var n sql.NullInt64
+		if err := n.Scan(src); err != nil {
+			return err
+		}
+		h.v.SetUint(uint64(n.Int64))
+	case reflect.Bool:
+		var b sql.NullBool
+		if err := b.Scan(src); err != nil {
+			return err
+		}
+		h.v.SetBool(b.Bool)
+	case reflect.Float32, reflect.Float64:
+		var f sql.NullFloat64
+		if err := f.Scan(src); err != nil {
+			return err
+		}
+		h.v.SetFloat(f.Float64)
+	case reflect.String:
+		var s sql.NullString
+		if err := s.Scan(src); err != nil {
+			return err
+		}
+		h.v.SetString(s.String)
+	case reflect.Struct:
+		if h.v.Type() != _timeType {
+			return fmt.Errorf("invalid nullable type: %v", h.v.Type())
+		}
+		var t sql.NullTime
+		if err := t.Scan(src); err != nil {
+			return err
+		}
+		h.v.Set(reflect.ValueOf(t.Time))
+	default:
+		return fmt.Errorf("invalid nullable type: %v", h.v.Type())
+	}
+	return nil
+}
+
+// isNullableType returns true if NULL can be scanned into typ by nullableHolder
+func isNullableType(typ reflect.Type) bool {
+	if typ == _timeType {
+		return true
+	}
+	return isNullableKind(typ.Kind())
+}
+
+func isNullableKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Bool, reflect.Float32, reflect.Float64, reflect.String:
+		return true
+	default:
+		return false
+	}
+}
+
+// scanDest returns destination which scans column into the corresponding field of elem
+func scanDest(elem reflect.Value, info *columnInfo, name string) interface{} {
+	field := elem.FieldByIndex(info.nameToIndex[name])
+	if IndexOfString(info.jsonNames, name) >= 0 {
+		return &jsonHolder{v: field.Addr().Interface()}
+	}
+
+	if c := info.codecs[name]; c != nil {
+		return &codecHolder{v: field, codec: c}
+	}
+
+	// Pointer field is set to nil for NULL, and Scanner handles NULL itself
+	if field.Kind() == reflect.Ptr || field.Addr().Type().Implements(_scannerType) {
+		return field.Addr().Interface()
+	}
+
+	if IndexOfString(info.nullableNames, name) >= 0 {
+		if !isNullableType(field.Type()) {
+			panic("invalid nullable type" + fmt.Sprint(field.Type()))
+		}
+		return &nullableHolder{v: field}
+	}
+	return field.Addr().Interface()
+}
+
+// fieldValue returns value of field as a statement argument.
+// Pointer is returned if only pointer implements driver.Valuer, e.g. Money
+func fieldValue(field reflect.Value) interface{} {
+	if field.Type().Implements(_valuerType) {
+		return field.Interface()
+	}
+	if reflect.PtrTo(field.Type()).Implements(_valuerType) {
+		if field.CanAddr() {
+			return field.Addr().Interface()
+		}
+		p := reflect.New(field.Type())
+		p.Elem().Set(field)
+		return p.Interface()
+	}
+	return field.Interface()
+}
+
+// scanDests returns destinations of all columns in order of info.names
+func scanDests(elem reflect.Value, info *columnInfo) []interface{} {
+	dests := make([]interface{}, len(info.names))
+	for i, name := range info.names {
+		dests[i] = scanDest(elem, info, name)
+	}
+	return dests
+}
+
+// newRecord returns pointer to a new value of typ. Nil pointers on the way to columns are allocated, e.g. embedded *struct,
+// while pointer columns are left nil
+func newRecord(typ reflect.Type) reflect.Value {
+	p := reflect.New(typ)
+	v := p.Elem()
+	for v.Kind() == reflect.Ptr {
+		v.Set(reflect.New(v.Type().Elem()))
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return p
+	}
+	for _, index := range getColumnInfo(v.Type()).indexes {
+		f := v
+		for _, i := range index[:len(index)-1] {
+			f = f.Field(i)
+			if f.Kind() == reflect.Ptr {
+				if f.IsNil() {
+					f.Set(reflect.New(f.Type().Elem()))
+				}
+				f = f.Elem()
+			}
+		}
+	}
+	return p
+}
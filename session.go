@@ -0,0 +1,216 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+
+	"github.com/gopub/log"
+)
+
+// session contains record operations shared by DBWrapper and TxWrapper
+type session struct {
+	exe     Executor
+	dialect Dialect
+	clock   Clock
+
+	//return ErrNotFound if no row matches primary key of record
+	errNotFound bool
+}
+
+func (s *session) Table(nameOrRecord interface{}) *Table {
+	if name, ok := nameOrRecord.(string); ok {
+		return &Table{
+			exe:         s.exe,
+			dialect:     s.dialect,
+			clock:       s.clock,
+			errNotFound: s.errNotFound,
+			name:        name,
+		}
+	}
+
+	typ := reflect.TypeOf(nameOrRecord)
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	t := &Table{
+		exe:         s.exe,
+		dialect:     s.dialect,
+		clock:       s.clock,
+		errNotFound: s.errNotFound,
+		name:        getTableName(nameOrRecord),
+	}
+	if typ.Kind() == reflect.Struct {
+		t.typ = typ
+	}
+	return t
+}
+
+func (s *session) Dialect() Dialect {
+	return s.dialect
+}
+
+// bindRaw prepares raw query like Table does: slice arguments are expanded, ? is replaced with placeholders of the dialect,
+// and arguments are encoded. Query without arguments is sent as it is
+func (s *session) bindRaw(query string, args []interface{}) (string, []interface{}) {
+	if len(args) == 0 {
+		return query, args
+	}
+	query, args = expandArgs(query, args)
+	return bindVars(s.dialect, query), encodeArgs(args)
+}
+
+func (s *session) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return s.ExecContext(context.Background(), query, args...)
+}
+
+func (s *session) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	query, args = s.bindRaw(query, args)
+	log.Debug(query, toReadableArgs(args))
+	return s.exe.ExecContext(ctx, query, args...)
+}
+
+func (s *session) Insert(record interface{}) error {
+	return s.InsertContext(context.Background(), record)
+}
+
+func (s *session) InsertContext(ctx context.Context, record interface{}) error {
+	return s.Table(getTableName(record)).InsertContext(ctx, record)
+}
+
+// BatchInsert inserts records with multi-row insert statements. Consecutive records of the same table are inserted together
+func (s *session) BatchInsert(values interface{}) error {
+	return s.BatchInsertContext(context.Background(), values)
+}
+
+func (s *session) BatchInsertContext(ctx context.Context, values interface{}) error {
+	l := reflect.ValueOf(values)
+	if l.Kind() != reflect.Slice {
+		return errors.New("not slice")
+	}
+
+	for i := 0; i < l.Len(); {
+		name := getTableName(l.Index(i).Interface())
+		j := i + 1
+		for ; j < l.Len() && getTableName(l.Index(j).Interface()) == name; j++ {
+		}
+		if err := s.Table(name).BatchInsertContext(ctx, l.Slice(i, j).Interface()); err != nil {
+			return err
+		}
+		i = j
+	}
+	return nil
+}
+
+func (s *session) Update(record interface{}) error {
+	return s.UpdateContext(context.Background(), record)
+}
+
+func (s *session) UpdateContext(ctx context.Context, record interface{}) error {
+	return s.Table(getTableName(record)).UpdateContext(ctx, record)
+}
+
+func (s *session) UpdateResult(record interface{}) (int64, error) {
+	return s.UpdateResultContext(context.Background(), record)
+}
+
+func (s *session) UpdateResultContext(ctx context.Context, record interface{}) (int64, error) {
+	return s.Table(getTableName(record)).UpdateResultContext(ctx, record)
+}
+
+func (s *session) UpdateColumns(record interface{}, columns ...string) (int64, error) {
+	return s.UpdateColumnsContext(context.Background(), record, columns...)
+}
+
+func (s *session) UpdateColumnsContext(ctx context.Context, record interface{}, columns ...string) (int64, error) {
+	return s.Table(getTableName(record)).UpdateColumnsContext(ctx, record, columns...)
+}
+
+func (s *session) Save(record interface{}) error {
+	return s.SaveContext(context.Background(), record)
+}
+
+func (s *session) SaveContext(ctx context.Context, record interface{}) error {
+	return s.Table(getTableName(record)).SaveContext(ctx, record)
+}
+
+func (s *session) Select(records interface{}, where interface{}, args ...interface{}) error {
+	return s.SelectContext(context.Background(), records, where, args...)
+}
+
+func (s *session) SelectContext(ctx context.Context, records interface{}, where interface{}, args ...interface{}) error {
+	return s.Table(getTableNameBySlice(records)).SelectContext(ctx, records, where, args...)
+}
+
+func (s *session) SelectOne(record interface{}, where interface{}, args ...interface{}) error {
+	return s.SelectOneContext(context.Background(), record, where, args...)
+}
+
+func (s *session) SelectOneContext(ctx context.Context, record interface{}, where interface{}, args ...interface{}) error {
+	return s.Table(getTableName(record)).SelectOneContext(ctx, record, where, args...)
+}
+
+// Each calls fn with every row matching conditions. fn is in format of func(record *Product) error, and table is decided by Product
+func (s *session) Each(fn interface{}, where interface{}, args ...interface{}) error {
+	return s.EachContext(context.Background(), fn, where, args...)
+}
+
+func (s *session) EachContext(ctx context.Context, fn interface{}, where interface{}, args ...interface{}) error {
+	record := reflect.New(eachRecordType(reflect.TypeOf(fn)).Elem()).Interface()
+	return s.Table(record).EachContext(ctx, fn, where, args...)
+}
+
+// Delete deletes record by primary key. If record has soft delete column, it's marked as deleted
+func (s *session) Delete(record interface{}) error {
+	return s.DeleteContext(context.Background(), record)
+}
+
+func (s *session) DeleteContext(ctx context.Context, record interface{}) error {
+	return s.Table(record).DeleteRecordContext(ctx, record)
+}
+
+// Get selects record by primary key values, e.g. db.Get(&product, 1)
+func (s *session) Get(record interface{}, pk ...interface{}) error {
+	return s.GetContext(context.Background(), record, pk...)
+}
+
+func (s *session) GetContext(ctx context.Context, record interface{}, pk ...interface{}) error {
+	return s.Table(record).GetContext(ctx, record, pk...)
+}
+
+// Exists returns true if a row with the primary key of record exists
+func (s *session) Exists(record interface{}) (bool, error) {
+	return s.ExistsContext(context.Background(), record)
+}
+
+func (s *session) ExistsContext(ctx context.Context, record interface{}) (bool, error) {
+	return s.Table(record).ExistsContext(ctx, record)
+}
+
+// Reload selects record again by its primary key
+func (s *session) Reload(record interface{}) error {
+	return s.ReloadContext(context.Background(), record)
+}
+
+func (s *session) ReloadContext(ctx context.Context, record interface{}) error {
+	return s.Table(record).ReloadContext(ctx, record)
+}
+
+// CreateTable creates table of record, e.g. db.CreateTable(&Product{}, sql.IfNotExists)
+func (s *session) CreateTable(record interface{}, opts ...DDLOption) error {
+	return s.CreateTableContext(context.Background(), record, opts...)
+}
+
+func (s *session) CreateTableContext(ctx context.Context, record interface{}, opts ...DDLOption) error {
+	return s.Table(record).CreateTableContext(ctx, record, opts...)
+}
+
+// DropTable drops table of record, e.g. db.DropTable(&Product{}, sql.IfExists)
+func (s *session) DropTable(record interface{}, opts ...DDLOption) error {
+	return s.DropTableContext(context.Background(), record, opts...)
+}
+
+func (s *session) DropTableContext(ctx context.Context, record interface{}, opts ...DDLOption) error {
+	return s.Table(record).DropTableContext(ctx, opts...)
+}
@@ -0,0 +1,94 @@
+package sql
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func methodNames(typ reflect.Type) map[string]bool {
+	m := make(map[string]bool, typ.NumMethod())
+	for i := 0; i < typ.NumMethod(); i++ {
+		m[typ.Method(i).Name] = true
+	}
+	return m
+}
+
+func TestSession_Parity(t *testing.T) {
+	dbMethods := methodNames(reflect.TypeOf(&DBWrapper{}))
+	txMethods := methodNames(reflect.TypeOf(&TxWrapper{}))
+
+	t.Run("DBAndTx", func(t *testing.T) {
+		// Operations which manage connections or transactions
+		dbOnly := map[string]bool{"Begin": true, "BeginTx": true, "Close": true, "DB": true, "MustExec": true,
+			"SetClock": true, "SetErrNotFound": true, "BatchUpdate": true, "BatchUpdateContext": true,
+			"MultiSave": true, "MultiSaveContext": true}
+		txOnly := map[string]bool{"Commit": true, "Rollback": true, "Tx": true}
+		for name := range dbMethods {
+			if !dbOnly[name] {
+				require.True(t, txMethods[name], "TxWrapper.%s is missing", name)
+			}
+		}
+		for name := range txMethods {
+			if !txOnly[name] {
+				require.True(t, dbMethods[name], "DBWrapper.%s is missing", name)
+			}
+		}
+	})
+
+	t.Run("Context", func(t *testing.T) {
+		// Methods which don't access database
+		noContext := map[string]bool{"Begin": true, "BeginTx": true, "Close": true, "DB": true, "Dialect": true, "MustExec": true,
+			"SetClock": true, "SetErrNotFound": true, "Table": true, "Commit": true, "Rollback": true, "Tx": true,
+			"Where": true, "OrderBy": true, "GroupBy": true, "Limit": true, "Offset": true, "Unscoped": true, "Paginate": true}
+		for _, typ := range []reflect.Type{reflect.TypeOf(&DBWrapper{}), reflect.TypeOf(&TxWrapper{}), reflect.TypeOf(&Table{})} {
+			methods := methodNames(typ)
+			for name := range methods {
+				if noContext[name] || strings.HasSuffix(name, "Context") {
+					continue
+				}
+				require.True(t, methods[name+"Context"], "%v.%sContext is missing", typ, name)
+			}
+		}
+	})
+}
+
+func TestTxWrapper(t *testing.T) {
+	type Product struct {
+		ID   int `sql:"primary key"`
+		Name string
+	}
+	db := openSQLite(t)
+	require.NoError(t, db.CreateTable(&Product{}))
+
+	tx, err := db.Begin()
+	require.NoError(t, err)
+	require.NoError(t, tx.Insert(&Product{ID: 1, Name: "apple"}))
+	require.NoError(t, tx.Rollback())
+	ok, err := db.Exists(&Product{ID: 1})
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	tx, err = db.BeginTx(context.Background(), nil)
+	require.NoError(t, err)
+	require.NoError(t, tx.Insert(&Product{ID: 1, Name: "apple"}))
+	require.NoError(t, tx.Update(&Product{ID: 1, Name: "pear"}))
+	n, err := tx.Table(&Product{}).Count("name = ?", "pear")
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	require.NoError(t, tx.Commit())
+
+	var p Product
+	require.NoError(t, db.Get(&p, 1))
+	require.Equal(t, "pear", p.Name)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.Error(t, db.InsertContext(ctx, &Product{ID: 2}))
+	require.Error(t, db.Table(&Product{}).SelectOneContext(ctx, &p, "id = ?", 1))
+	_, err = db.BeginTx(ctx, nil)
+	require.Error(t, err)
+}
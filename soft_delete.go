@@ -0,0 +1,124 @@
+package sql
+
+import (
+	"context"
+
+	"github.com/gopub/log"
+)
+
+// Unscoped includes soft deleted rows in query, and makes Delete remove rows
+func (q *Query) Unscoped() *Query {
+	q.unscoped = true
+	return q
+}
+
+// scoped returns a query which excludes soft deleted rows of records described by info
+func (q *Query) scoped(info *columnInfo) *Query {
+	if q.unscoped || info == nil || len(info.deletedName) == 0 {
+		return q
+	}
+	sq := *q
+	sq.where = make([]Condition, len(q.where), len(q.where)+1)
+	copy(sq.where, q.where)
+	sq.where = append(sq.where, IsNull(q.table.dialect.QuoteIdent(info.deletedName)))
+	return &sq
+}
+
+// Restore clears soft delete column of rows matching conditions
+func (q *Query) Restore() (int64, error) {
+	return q.RestoreContext(context.Background())
+}
+
+func (q *Query) RestoreContext(ctx context.Context) (int64, error) {
+	if len(q.where) == 0 {
+		panic("where is empty")
+	}
+
+	info := q.table.info()
+	if info == nil || len(info.deletedName) == 0 {
+		panic("no deleted_at column in table " + q.table.name)
+	}
+
+	query, args := q.updateQuery(map[string]interface{}{info.deletedName: nil})
+	result, err := q.table.exec(ctx, query, args)
+	if err != nil {
+		log.Error(err)
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// Unscoped starts a query including soft deleted rows
+func (t *Table) Unscoped() *Query {
+	return t.newQuery().Unscoped()
+}
+
+// HardDelete removes rows even if table has soft delete column, and returns number of removed rows
+func (t *Table) HardDelete(where interface{}, args ...interface{}) (int64, error) {
+	return t.HardDeleteContext(context.Background(), where, args...)
+}
+
+func (t *Table) HardDeleteContext(ctx context.Context, where interface{}, args ...interface{}) (int64, error) {
+	if where == nil || where == "" {
+		panic("where is empty")
+	}
+	return t.Unscoped().Where(where, args...).DeleteContext(ctx)
+}
+
+// Restore clears soft delete column of rows matching where, and returns number of restored rows
+func (t *Table) Restore(where interface{}, args ...interface{}) (int64, error) {
+	return t.RestoreContext(context.Background(), where, args...)
+}
+
+func (t *Table) RestoreContext(ctx context.Context, where interface{}, args ...interface{}) (int64, error) {
+	if where == nil || where == "" {
+		panic("where is empty")
+	}
+	return t.Unscoped().Where(where, args...).RestoreContext(ctx)
+}
+
+// DeleteRecord deletes record by primary key. If record has soft delete column, it's set instead of removing the row.
+// It returns ErrNotFound if no row matches and DBWrapper.SetErrNotFound is enabled
+func (t *Table) DeleteRecord(record interface{}) error {
+	return t.DeleteRecordContext(context.Background(), record)
+}
+
+func (t *Table) DeleteRecordContext(ctx context.Context, record interface{}) error {
+	v := getStructValue(record)
+	info := getColumnInfo(v.Type())
+	if len(info.pkNames) == 0 {
+		panic("no primary key")
+	}
+
+	q := t.pkQuery(v.Type(), info, pkValues(v, info))
+
+	if len(info.deletedName) == 0 {
+		n, err := q.DeleteContext(ctx)
+		if err == nil && n == 0 && t.errNotFound {
+			return ErrNotFound
+		}
+		return err
+	}
+
+	now := t.now()
+	query, args := q.scoped(info).updateQuery(map[string]interface{}{info.deletedName: deletedAtValue(info, v.Type(), now)})
+	result, err := t.exec(ctx, query, args)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+	if n == 0 {
+		// Record isn't found or has been deleted
+		if t.errNotFound {
+			return ErrNotFound
+		}
+		return nil
+	}
+	setDeletedAt(v, info, now)
+	return nil
+}
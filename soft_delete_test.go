@@ -0,0 +1,87 @@
+package sql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTable_SoftDelete(t *testing.T) {
+	t.Run("Time", func(t *testing.T) {
+		type Item struct {
+			ID        int       `sql:"primary key"`
+			DeletedAt time.Time `sql:"deleted_at"`
+		}
+		db := openSQLite(t)
+		require.NoError(t, db.CreateTable(&Item{}))
+		require.NoError(t, db.Insert(&Item{ID: 1}))
+		require.NoError(t, db.Insert(&Item{ID: 2}))
+
+		p := &Item{ID: 1}
+		require.NoError(t, db.Delete(p))
+		require.False(t, p.DeletedAt.IsZero())
+
+		// Deleted record isn't deleted again
+		p = &Item{ID: 1}
+		require.NoError(t, db.Delete(p))
+		require.True(t, p.DeletedAt.IsZero())
+
+		n, err := db.Table(&Item{}).Delete("id = ?", 2)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), n)
+		count, err := db.Table(&Item{}).Count("")
+		require.NoError(t, err)
+		require.Zero(t, count)
+
+		require.NoError(t, db.Table(&Item{}).Unscoped().Where("id = ?", 2).SelectOne(p))
+		require.False(t, p.DeletedAt.IsZero())
+	})
+
+	t.Run("TimePointer", func(t *testing.T) {
+		type Item struct {
+			ID        int        `sql:"primary key"`
+			DeletedAt *time.Time `sql:"deleted_at"`
+		}
+		db := openSQLite(t)
+		require.NoError(t, db.CreateTable(&Item{}))
+		require.NoError(t, db.Insert(&Item{ID: 1}))
+
+		p := &Item{ID: 1}
+		require.NoError(t, db.Delete(p))
+		require.NotNil(t, p.DeletedAt)
+
+		n, err := db.Table(&Item{}).Restore("id = ?", 1)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), n)
+		require.NoError(t, db.Get(p, 1))
+		require.Nil(t, p.DeletedAt)
+	})
+
+	t.Run("TableName", func(t *testing.T) {
+		type Note struct {
+			ID        int   `sql:"primary key"`
+			DeletedAt int64 `sql:"deleted_at"`
+		}
+		db := openSQLite(t)
+		Register(&Note{})
+		_, err := db.Exec("CREATE TABLE notes (id INTEGER PRIMARY KEY, deleted_at BIGINT)")
+		require.NoError(t, err)
+		_, err = db.Exec("INSERT INTO notes (id) VALUES (1), (2)")
+		require.NoError(t, err)
+
+		// Table created by name finds soft delete column of registered record type
+		n, err := db.Table("notes").Delete("id = ?", 1)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), n)
+		count, err := db.Table("notes").Count("")
+		require.NoError(t, err)
+		require.Equal(t, 1, count)
+		count, err = db.Table("notes").Unscoped().Count()
+		require.NoError(t, err)
+		require.Equal(t, 2, count)
+		n, err = db.Table("notes").HardDelete("id = ?", 1)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), n)
+	})
+}
@@ -1,9 +1,12 @@
 package sql
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/gopub/log"
 )
@@ -30,16 +33,32 @@ var (
 	ErrNoRows   = sql.ErrNoRows
 	ErrTxDone   = sql.ErrTxDone
 	ErrConnDone = sql.ErrConnDone
+
+	// ErrStaleRecord is returned if no row matches primary key and version of the record,
+	// which means the row has been updated or deleted by others
+	ErrStaleRecord = errors.New("stale record")
+
+	// ErrInvalidCursor is returned if cursor of Paginator is malformed or doesn't match the orders
+	ErrInvalidCursor = errors.New("invalid cursor")
+
+	// ErrNotFound is returned by Update and Delete of a record if no row matches its primary key.
+	// It's enabled by DBWrapper.SetErrNotFound
+	ErrNotFound = errors.New("not found")
 )
 
+// Clock provides current time
+type Clock interface {
+	Now() time.Time
+}
+
 type ColumnScanner interface {
 	Scan(dest ...interface{}) error
 }
 
 type Executor interface {
-	Exec(query string, args ...interface{}) (sql.Result, error)
-	Query(query string, args ...interface{}) (*sql.Rows, error)
-	QueryRow(query string, args ...interface{}) *sql.Row
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
 }
 
 func Escape(s string) string {
@@ -11,9 +11,8 @@ import (
 	"github.com/gopub/sql"
 )
 
-type Clock interface {
-	Now() time.Time
-}
+// Clock is an alias of sql.Clock
+type Clock = sql.Clock
 
 type KVStore struct {
 	ID       interface{}
@@ -0,0 +1,39 @@
+package sql
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/require"
+)
+
+// openSQLite opens an in-memory database which only lives in its single connection
+func openSQLite(t *testing.T) *DBWrapper {
+	db, err := NewDBWrapper("sqlite3", ":memory:")
+	require.NoError(t, err)
+	db.DB().SetMaxOpenConns(1)
+	t.Cleanup(func() {
+		db.Close()
+	})
+	return db
+}
+
+func TestNewDBWrapper_Generic(t *testing.T) {
+	sql.Register("sqlite3_generic", &sqlite3.SQLiteDriver{})
+	db, err := NewDBWrapper("sqlite3_generic", ":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+	require.Equal(t, Generic, db.Dialect())
+
+	type Product struct {
+		ID   int `sql:"primary key"`
+		Name string
+	}
+	require.NoError(t, db.CreateTable(&Product{}))
+	require.NoError(t, db.Insert(&Product{ID: 1, Name: "apple"}))
+	var l []*Product
+	require.NoError(t, db.Select(&l, "name = ?", "apple"))
+	require.Len(t, l, 1)
+	require.Error(t, db.Save(&Product{ID: 1, Name: "pear"}))
+}
@@ -2,13 +2,10 @@ package sql
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"encoding/json"
-	"fmt"
 	"reflect"
-	"strings"
-
-	"github.com/gopub/types"
 
 	"github.com/gopub/conv"
 	"github.com/gopub/log"
@@ -69,521 +66,467 @@ func isEmpty(jsonData []byte) bool {
 }
 
 type Table struct {
-	exe        Executor
-	driverName string
-	name       string
+	exe         Executor
+	dialect     Dialect
+	clock       Clock
+	errNotFound bool
+	name        string
+
+	//record type if table is created by a record, used to find soft delete column
+	typ reflect.Type
 }
 
-func (t *Table) Insert(record interface{}) error {
-	query, values, err := t.prepareInsertQuery(record)
-	if err != nil {
-		log.Error(err)
-		return err
+// recordType returns record type of table. If table is created by name, it returns the known record type
+// with soft delete column of this table, or nil
+func (t *Table) recordType() reflect.Type {
+	if t.typ != nil {
+		return t.typ
+	}
+	if typ, ok := _tableToSoftDeleteType.Load(t.name); ok {
+		return typ.(reflect.Type)
 	}
+	return nil
+}
 
-	if log.GetLevel() <= log.DebugLevel {
-		log.Debug(query, toReadableArgs(values))
+// info returns column info of record type, or nil if record type is unknown
+func (t *Table) info() *columnInfo {
+	if typ := t.recordType(); typ != nil {
+		return getColumnInfo(typ)
 	}
-	result, err := t.exe.Exec(query, values...)
+	return nil
+}
+
+func (t *Table) Insert(record interface{}) error {
+	return t.InsertContext(context.Background(), record)
+}
+
+func (t *Table) InsertContext(ctx context.Context, record interface{}) error {
+	v := getStructValue(record)
+	info := getColumnInfo(v.Type())
+	t.stampInsert(v, info)
+	if len(info.versionName) > 0 && v.FieldByIndex(info.nameToIndex[info.versionName]).Int() == 0 {
+		return t.insertVersioned(ctx, record, v, info)
+	}
+
+	query, values, err := t.prepareInsertQuery(record)
 	if err != nil {
 		log.Error(err)
 		return err
 	}
+	return t.insert(ctx, record, query, values)
+}
+
+// insert executes insert query and fills auto increment field with generated value
+func (t *Table) insert(ctx context.Context, record interface{}, query string, values []interface{}) error {
 	v := getStructValue(record)
 	info := getColumnInfo(v.Type())
-	if len(info.aiName) > 0 && v.FieldByIndex(info.nameToIndex[info.aiName]).Int() == 0 {
-		id, err := result.LastInsertId()
+	if !isAutoIncrementZero(v, info) {
+		_, err := t.exec(ctx, query, values)
 		if err != nil {
 			log.Error(err)
 			return err
 		}
-		v.FieldByIndex(info.nameToIndex[info.aiName]).SetInt(id)
+		track(v, info, info.names)
+		return nil
 	}
 
+	var id int64
+	if returning := t.dialect.Returning(info.aiName); len(returning) > 0 {
+		err := t.queryRow(ctx, query+" "+returning, values).Scan(&id)
+		if err != nil {
+			log.Error(err)
+			return err
+		}
+	} else {
+		result, err := t.exec(ctx, query, values)
+		if err != nil {
+			log.Error(err)
+			return err
+		}
+		id, err = result.LastInsertId()
+		if err != nil {
+			log.Error(err)
+			return err
+		}
+	}
+	v.FieldByIndex(info.nameToIndex[info.aiName]).SetInt(id)
+	track(v, info, info.names)
 	return nil
 }
 
 func (t *Table) prepareInsertQuery(record interface{}) (string, []interface{}, error) {
 	v := getStructValue(record)
 	info := getColumnInfo(v.Type())
-
-	var columns []string
-	values := make([]interface{}, 0, len(info.indexes))
-	if len(info.aiName) > 0 && v.FieldByIndex(info.nameToIndex[info.aiName]).Int() == 0 {
-		columns = info.notAINames
-	} else {
-		columns = info.names
-	}
-
+	columns := insertColumns(v, info)
+	values := make([]interface{}, 0, len(columns))
 	for _, name := range columns {
-		fv, err := t.getFieldValueByName(v, info, name)
+		fv, err := getFieldValueByName(v, info, name)
 		if err != nil {
 			return "", nil, err
 		}
 		values = append(values, fv)
 	}
+	return t.insertQuery(columns, 1), values, nil
+}
 
+// insertQuery returns insert statement of columns with placeholders for numRows rows
+func (t *Table) insertQuery(columns []string, numRows int) string {
 	var buf bytes.Buffer
 	buf.WriteString("INSERT INTO ")
-	buf.WriteString(t.name)
+	buf.WriteString(t.dialect.QuoteIdent(t.name))
 	buf.WriteString("(")
-	buf.WriteString(strings.Join(columns, ", "))
-	buf.WriteString(") VALUES (")
-	buf.WriteString(strings.Repeat("?, ", len(columns)))
-	buf.Truncate(buf.Len() - 2)
-	buf.WriteString(")")
-	return buf.String(), values, nil
+	buf.WriteString(quoteIdents(t.dialect, columns))
+	buf.WriteString(") VALUES ")
+	row := "(" + placeholders(len(columns)) + ")"
+	for i := 0; i < numRows; i++ {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(row)
+	}
+	return buf.String()
+}
+
+// insertColumns returns columns to insert. Auto increment column is skipped if it's zero
+func insertColumns(v reflect.Value, info *columnInfo) []string {
+	if isAutoIncrementZero(v, info) {
+		return info.notAINames
+	}
+	return info.names
+}
+
+// isAutoIncrementZero returns true if auto increment field is zero which means the value should be generated by db
+func isAutoIncrementZero(v reflect.Value, info *columnInfo) bool {
+	return len(info.aiName) > 0 && v.FieldByIndex(info.nameToIndex[info.aiName]).Int() == 0
 }
 
 func (t *Table) Update(record interface{}) error {
+	return t.UpdateContext(context.Background(), record)
+}
+
+// UpdateContext updates record by primary key. If record embeds Tracked, only changed columns are written.
+// It returns ErrNotFound if no row matches and DBWrapper.SetErrNotFound is enabled
+func (t *Table) UpdateContext(ctx context.Context, record interface{}) error {
+	_, err := t.UpdateResultContext(ctx, record)
+	return err
+}
+
+// UpdateResult is the same as Update, but returns number of affected rows.
+// It returns 0 without running statement if record embeds Tracked and nothing changed
+func (t *Table) UpdateResult(record interface{}) (int64, error) {
+	return t.UpdateResultContext(context.Background(), record)
+}
+
+func (t *Table) UpdateResultContext(ctx context.Context, record interface{}) (int64, error) {
 	v := getStructValue(record)
 	info := getColumnInfo(v.Type())
 	if len(info.pkNames) == 0 {
 		panic("no primary key. please use Insert operation")
 	}
 
-	var buf bytes.Buffer
-	buf.WriteString("UPDATE ")
-	buf.WriteString(t.name)
-	buf.WriteString(" SET ")
-	for i, c := range info.notPKNames {
-		if i > 0 {
-			buf.WriteString(", ")
+	columns := info.notPKNames
+	if changed, ok := changedColumns(v, info, columns); ok {
+		if len(changed) == 0 {
+			return 0, nil
 		}
-		buf.WriteString(c)
-		buf.WriteString(" = ?")
+		columns = changed
 	}
-
-	buf.WriteString(" WHERE ")
-	for i, c := range info.pkNames {
-		if i > 0 {
-			buf.WriteString(" and ")
-		}
-		buf.WriteString(c)
-		buf.WriteString(" = ?")
+	n, err := t.updateByPK(ctx, v, info, columns)
+	if err == nil && n == 0 && t.errNotFound {
+		return 0, ErrNotFound
 	}
+	return n, err
+}
 
-	args := make([]interface{}, 0, len(info.indexes))
-	for _, name := range info.notPKNames {
-		fv, err := t.getFieldValueByName(v, info, name)
-		if err != nil {
-			return err
-		}
-		args = append(args, fv)
-	}
+// UpdateColumns updates the named columns of record by primary key, and returns number of affected rows
+// e.g. db.Table("products").UpdateColumns(p, "price", "updated_at")
+func (t *Table) UpdateColumns(record interface{}, columns ...string) (int64, error) {
+	return t.UpdateColumnsContext(context.Background(), record, columns...)
+}
 
-	for _, name := range info.pkNames {
-		args = append(args, v.FieldByIndex(info.nameToIndex[name]).Interface())
+func (t *Table) UpdateColumnsContext(ctx context.Context, record interface{}, columns ...string) (int64, error) {
+	v := getStructValue(record)
+	info := getColumnInfo(v.Type())
+	if len(info.pkNames) == 0 {
+		panic("no primary key. please use Insert operation")
 	}
-
-	query := buf.String()
-	if log.GetLevel() <= log.DebugLevel {
-		log.Debug(query, toReadableArgs(args))
+	if len(columns) == 0 {
+		panic("columns are empty")
 	}
-	_, err := t.exe.Exec(query, args...)
-	return err
-}
-
-func (t *Table) Save(record interface{}) error {
-	switch t.driverName {
-	case "mysql":
-		return t.mysqlSave(record)
-	case "sqlite3":
-		return t.sqliteSave(record)
-	default:
-		panic("Save operation is not supported for driver: " + t.driverName)
+	for _, c := range columns {
+		if _, ok := info.nameToIndex[c]; !ok {
+			panic("no column " + c + " in " + v.Type().String())
+		}
+		if IndexOfString(info.pkNames, c) >= 0 {
+			panic("cannot update primary key column " + c)
+		}
 	}
+	return t.updateByPK(ctx, v, info, columns)
 }
 
-func (t *Table) mysqlSave(record interface{}) error {
-	query, values, err := t.prepareInsertQuery(record)
-	if err != nil {
-		log.Error(err)
-		return err
+// updateByPK updates columns of record v by primary key, and refreshes updated_at.
+// If record has version column, version is checked and increased, and ErrStaleRecord is returned if no row matches
+func (t *Table) updateByPK(ctx context.Context, v reflect.Value, info *columnInfo, columns []string) (int64, error) {
+	// version is increased by statement, created_at is never updated, and updated_at is always updated
+	l := make([]string, 0, len(columns)+1)
+	for _, c := range columns {
+		if c != info.versionName && c != info.createdName && c != info.updatedName {
+			l = append(l, c)
+		}
 	}
-
-	v := getStructValue(record)
-	info := getColumnInfo(v.Type())
+	if len(info.updatedName) > 0 {
+		t.stampUpdate(v, info, t.now())
+		l = append(l, info.updatedName)
+	}
+	columns = l
 
 	var buf bytes.Buffer
-	buf.WriteString(query)
-	buf.WriteString(" ON DUPLICATE KEY UPDATE ")
-	for i, name := range info.notPKNames {
-		if name == "created_at" {
-			continue
-		}
-
+	buf.WriteString("UPDATE ")
+	buf.WriteString(t.dialect.QuoteIdent(t.name))
+	buf.WriteString(" SET ")
+	for i, c := range columns {
 		if i > 0 {
 			buf.WriteString(", ")
 		}
-		buf.WriteString(name)
+		buf.WriteString(t.dialect.QuoteIdent(c))
 		buf.WriteString(" = ?")
-		fv, err := t.getFieldValueByName(v, info, name)
-		if err != nil {
-			return err
+	}
+	if len(info.versionName) > 0 {
+		if len(columns) > 0 {
+			buf.WriteString(", ")
 		}
-		values = append(values, fv)
+		version := t.dialect.QuoteIdent(info.versionName)
+		buf.WriteString(version + " = " + version + " + 1")
 	}
 
-	query = buf.String()
-
-	if log.GetLevel() <= log.DebugLevel {
-		log.Debug(query, toReadableArgs(values))
+	buf.WriteString(" WHERE ")
+	for i, c := range info.pkNames {
+		if i > 0 {
+			buf.WriteString(" AND ")
+		}
+		buf.WriteString(t.dialect.QuoteIdent(c))
+		buf.WriteString(" = ?")
+	}
+	if len(info.versionName) > 0 {
+		buf.WriteString(" AND ")
+		buf.WriteString(t.dialect.QuoteIdent(info.versionName))
+		buf.WriteString(" = ?")
 	}
 
-	result, err := t.exe.Exec(query, values...)
-	if len(info.aiName) > 0 && v.FieldByIndex(info.nameToIndex[info.aiName]).Int() == 0 {
-		id, err := result.LastInsertId()
+	args := make([]interface{}, 0, len(columns)+len(info.pkNames)+1)
+	for _, name := range columns {
+		fv, err := getFieldValueByName(v, info, name)
 		if err != nil {
 			log.Error(err)
-			return err
+			return 0, err
 		}
-		v.FieldByIndex(info.nameToIndex[info.aiName]).SetInt(id)
-	}
-	return err
-}
-
-func (t *Table) sqliteSave(record interface{}) error {
-	query, values, err := t.prepareInsertQuery(record)
-	if err != nil {
-		log.Error(err)
-		return err
+		args = append(args, fv)
 	}
 
-	query = strings.Replace(query, "INSERT INTO", "INSERT OR REPLACE INTO", 1)
-	v := getStructValue(record)
-	info := getColumnInfo(v.Type())
-
-	if log.GetLevel() <= log.DebugLevel {
-		log.Debug(query, toReadableArgs(values))
+	for _, name := range info.pkNames {
+		args = append(args, columnValue(v, info, name))
 	}
 
-	result, err := t.exe.Exec(query, values...)
-	if len(info.aiName) > 0 && v.FieldByIndex(info.nameToIndex[info.aiName]).Int() == 0 {
-		id, err := result.LastInsertId()
+	if len(info.versionName) == 0 {
+		result, err := t.exec(ctx, buf.String(), args)
 		if err != nil {
 			log.Error(err)
-			return err
+			return 0, err
 		}
-		v.FieldByIndex(info.nameToIndex[info.aiName]).SetInt(id)
+		track(v, info, columns)
+		return result.RowsAffected()
 	}
-	return err
-}
 
-func (t *Table) Select(records interface{}, where string, args ...interface{}) error {
-	v := reflect.ValueOf(records)
-	if v.Kind() != reflect.Ptr {
-		panic("must be a pointer to slice")
+	versionField := v.FieldByIndex(info.nameToIndex[info.versionName])
+	args = append(args, versionField.Interface())
+	result, err := t.exec(ctx, buf.String(), args)
+	if err != nil {
+		log.Error(err)
+		return 0, err
 	}
-
-	if v.IsNil() && !v.CanSet() {
-		panic("cannot be set value")
+	n, err := result.RowsAffected()
+	if err != nil {
+		log.Error(err)
+		return 0, err
 	}
-
-	sliceType := v.Type().Elem()
-	if sliceType.Kind() != reflect.Slice {
-		panic("must be a pointer to slice")
+	if n == 0 {
+		return 0, ErrStaleRecord
 	}
+	versionField.SetInt(versionField.Int() + 1)
+	track(v, info, append(columns, info.versionName))
+	return n, nil
+}
 
-	isPtr := false
-	elemType := sliceType.Elem()
-	if elemType.Kind() == reflect.Ptr {
-		elemType = elemType.Elem()
-		isPtr = true
-	}
+// UpdateWhere sets columns to values in m on rows matching where, and returns number of affected rows
+// e.g. db.Table("products").UpdateWhere(map[string]interface{}{"price": 0.1}, "name = ?", "apple")
+func (t *Table) UpdateWhere(m map[string]interface{}, where interface{}, args ...interface{}) (int64, error) {
+	return t.UpdateWhereContext(context.Background(), m, where, args...)
+}
 
-	if elemType.Kind() != reflect.Struct {
-		panic("slice element must be a struct or pointer to struct")
+func (t *Table) UpdateWhereContext(ctx context.Context, m map[string]interface{}, where interface{}, args ...interface{}) (int64, error) {
+	if where == nil || where == "" {
+		panic("where is empty")
 	}
+	return t.Where(where, args...).UpdateContext(ctx, m)
+}
 
-	fi := getColumnInfo(elemType)
-
-	var buf bytes.Buffer
-	buf.WriteString("SELECT ")
-	buf.WriteString(strings.Join(fi.names, ", "))
-	buf.WriteString(" FROM ")
-	buf.WriteString(t.name)
-	if len(where) > 0 {
-		buf.WriteString(" WHERE ")
-		buf.WriteString(where)
-	}
-	query := buf.String()
+// Save inserts record, or updates it if primary key conflicts
+func (t *Table) Save(record interface{}) error {
+	return t.SaveContext(context.Background(), record)
+}
 
-	if log.GetLevel() <= log.DebugLevel {
-		log.Debug(query, toReadableArgs(args))
+func (t *Table) SaveContext(ctx context.Context, record interface{}) error {
+	v := getStructValue(record)
+	info := getColumnInfo(v.Type())
+	t.stampInsert(v, info)
+	if len(info.versionName) > 0 {
+		return t.saveVersioned(ctx, record, v, info)
 	}
 
-	rows, err := t.exe.Query(query, args...)
+	query, values, err := t.prepareInsertQuery(record)
 	if err != nil {
 		log.Error(err)
 		return err
 	}
-	defer rows.Close()
-
-	if v.IsNil() {
-		v.Set(reflect.New(sliceType))
-	}
-	sliceValue := v.Elem()
-	fields := make([]interface{}, len(fi.indexes))
-	for rows.Next() {
-		ptrToElem := types.DeepNew(elemType)
-		elem := ptrToElem.Elem()
-		for i, idx := range fi.indexes {
-			if IndexOfString(fi.jsonNames, fi.names[i]) >= 0 {
-				var data []byte
-				fields[i] = &data
-			} else if IndexOfString(fi.nullableNames, fi.names[i]) >= 0 {
-				switch elem.FieldByIndex(idx).Kind() {
-				case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
-					reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-					var v sql.NullInt64
-					fields[i] = &v
-				case reflect.Bool:
-					var b sql.NullBool
-					fields[i] = &b
-				case reflect.Float32, reflect.Float64:
-					var v sql.NullFloat64
-					fields[i] = &v
-				case reflect.String:
-					var v sql.NullString
-					fields[i] = &v
-				default:
-					panic("invalid nullable type" + fmt.Sprint(elem.FieldByIndex(idx).Type()))
-				}
-			} else {
-				fields[i] = elem.FieldByIndex(idx).Addr().Interface()
-			}
-		}
-
-		err = rows.Scan(fields...)
-		if err != nil {
-			log.Error(err)
-			return err
-		}
-
-		for _, name := range fi.jsonNames {
-			idx := fi.nameToIndex[name]
-			i := IndexOfString(fi.names, name)
-			addr := fields[i]
-			data := reflect.ValueOf(addr).Elem().Interface()
-			err = json.Unmarshal(data.([]byte), elem.FieldByIndex(idx).Addr().Interface())
-			if err != nil {
-				log.Error(err)
-				return err
-			}
-		}
 
-		for _, name := range fi.nullableNames {
-			idx := fi.nameToIndex[name]
-			i := IndexOfString(fi.names, name)
-			addr := fields[i]
-			switch v := reflect.ValueOf(addr).Elem().Interface().(type) {
-			case sql.NullString:
-				if v.Valid {
-					elem.FieldByIndex(idx).SetString(v.String)
-				}
-			case sql.NullFloat64:
-				if v.Valid {
-					elem.FieldByIndex(idx).SetFloat(v.Float64)
-				}
-			case sql.NullBool:
-				if v.Valid {
-					elem.FieldByIndex(idx).SetBool(v.Bool)
-				}
-			case sql.NullInt64:
-				if v.Valid {
-					elem.FieldByIndex(idx).SetInt(v.Int64)
-				}
-			default:
-				panic("invalid type:" + fmt.Sprint(v))
-			}
+	// created_at is never overwritten, even if it's not tagged
+	createdName := info.createdName
+	if len(createdName) == 0 {
+		createdName = "created_at"
+	}
+	updateNames := make([]string, 0, len(info.notPKNames))
+	for _, name := range info.notPKNames {
+		if name != createdName {
+			updateNames = append(updateNames, name)
 		}
+	}
 
-		if isPtr {
-			sliceValue = reflect.Append(sliceValue, ptrToElem)
-		} else {
-			sliceValue = reflect.Append(sliceValue, elem)
-		}
+	query, err = t.dialect.Upsert(query, info.pkNames, updateNames)
+	if err != nil {
+		log.Error(err)
+		return err
 	}
-	v.Elem().Set(sliceValue)
-	return nil
+	return t.insert(ctx, record, query, values)
 }
 
-func (t *Table) SelectOne(record interface{}, where string, args ...interface{}) error {
-	rv := reflect.ValueOf(record)
-	if rv.Kind() != reflect.Ptr {
-		panic("not pointer to a struct")
+// saveVersioned inserts record if its version is zero, otherwise updates it on condition that version is not changed
+func (t *Table) saveVersioned(ctx context.Context, record interface{}, v reflect.Value, info *columnInfo) error {
+	if v.FieldByIndex(info.nameToIndex[info.versionName]).Int() == 0 {
+		return t.insertVersioned(ctx, record, v, info)
 	}
+	_, err := t.updateByPK(ctx, v, info, info.notPKNames)
+	return err
+}
 
-	//Store result in ev. If failed, don't change record's value
-	ev := types.DeepNew(rv.Elem().Type()).Elem()
-	elem := ev
-	if elem.Kind() == reflect.Ptr {
-		elem = elem.Elem()
+// insertVersioned inserts record with version 1
+func (t *Table) insertVersioned(ctx context.Context, record interface{}, v reflect.Value, info *columnInfo) error {
+	versionField := v.FieldByIndex(info.nameToIndex[info.versionName])
+	versionField.SetInt(1)
+	query, values, err := t.prepareInsertQuery(record)
+	if err == nil {
+		err = t.insert(ctx, record, query, values)
 	}
-
-	if elem.Kind() != reflect.Struct {
-		panic("not pointer to a struct")
+	if err != nil {
+		log.Error(err)
+		versionField.SetInt(0)
 	}
+	return err
+}
 
-	info := getColumnInfo(elem.Type())
+func (t *Table) newQuery() *Query {
+	return &Query{table: t}
+}
 
-	var buf bytes.Buffer
-	buf.WriteString("SELECT ")
-	buf.WriteString(strings.Join(info.names, ", "))
-	buf.WriteString(" FROM ")
-	buf.WriteString(t.name)
-	if len(where) > 0 {
-		buf.WriteString(" WHERE ")
-		buf.WriteString(where)
-	}
-	query := buf.String()
+// Where starts a query with condition. where can be a string with args or a Condition
+func (t *Table) Where(where interface{}, args ...interface{}) *Query {
+	return t.newQuery().Where(where, args...)
+}
 
-	if log.GetLevel() <= log.DebugLevel {
-		log.Debug(query, toReadableArgs(args))
-	}
+func (t *Table) OrderBy(orders ...string) *Query {
+	return t.newQuery().OrderBy(orders...)
+}
 
-	fieldAddrs := make([]interface{}, len(info.indexes))
-	for i, idx := range info.indexes {
-		if IndexOfString(info.jsonNames, info.names[i]) >= 0 {
-			var data []byte
-			fieldAddrs[i] = &data
-		} else if IndexOfString(info.nullableNames, info.names[i]) >= 0 {
-			switch elem.FieldByIndex(idx).Kind() {
-			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
-				reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-				var v sql.NullInt64
-				fieldAddrs[i] = &v
-			case reflect.Bool:
-				var b sql.NullBool
-				fieldAddrs[i] = &b
-			case reflect.Float32, reflect.Float64:
-				var v sql.NullFloat64
-				fieldAddrs[i] = &v
-			case reflect.String:
-				var v sql.NullString
-				fieldAddrs[i] = &v
-			default:
-				panic("invalid nullable type" + fmt.Sprint(elem.FieldByIndex(idx).Type()))
-			}
-		} else {
-			fieldAddrs[i] = elem.FieldByIndex(idx).Addr().Interface()
-		}
-	}
-	err := t.exe.QueryRow(query, args...).Scan(fieldAddrs...)
-	if err != nil {
-		if err != sql.ErrNoRows {
-			log.Error(err)
-		}
-		return err
-	}
+func (t *Table) GroupBy(columns ...string) *Query {
+	return t.newQuery().GroupBy(columns...)
+}
 
-	for _, name := range info.jsonNames {
-		idx := info.nameToIndex[name]
-		i := IndexOfString(info.names, name)
-		addr := fieldAddrs[i]
-		data := reflect.ValueOf(addr).Elem().Interface()
-		err = json.Unmarshal(data.([]byte), elem.FieldByIndex(idx).Addr().Interface())
-		if err != nil {
-			log.Error(err)
-			return err
-		}
-	}
+func (t *Table) Limit(limit int64) *Query {
+	return t.newQuery().Limit(limit)
+}
 
-	for _, name := range info.nullableNames {
-		idx := info.nameToIndex[name]
-		i := IndexOfString(info.names, name)
-		addr := fieldAddrs[i]
-		switch v := reflect.ValueOf(addr).Elem().Interface().(type) {
-		case sql.NullString:
-			if v.Valid {
-				elem.FieldByIndex(idx).SetString(v.String)
-			}
-		case sql.NullFloat64:
-			if v.Valid {
-				elem.FieldByIndex(idx).SetFloat(v.Float64)
-			}
-		case sql.NullBool:
-			if v.Valid {
-				elem.FieldByIndex(idx).SetBool(v.Bool)
-			}
-		case sql.NullInt64:
-			if v.Valid {
-				elem.FieldByIndex(idx).SetInt(v.Int64)
-			}
-		default:
-			panic("invalid type:" + fmt.Sprint(v))
-		}
-	}
+func (t *Table) Offset(offset int64) *Query {
+	return t.newQuery().Offset(offset)
+}
 
-	if err == nil {
-		rv.Elem().Set(ev)
-	}
-	return err
+func (t *Table) Select(records interface{}, where interface{}, args ...interface{}) error {
+	return t.SelectContext(context.Background(), records, where, args...)
 }
 
-/*
-func (t *Table) QueryRow(query string, args ...interface{}) *Row {
-	row := t.exe.QueryRow(query, args...)
-	return (*Row)(row)
+func (t *Table) SelectContext(ctx context.Context, records interface{}, where interface{}, args ...interface{}) error {
+	return t.Where(where, args...).SelectContext(ctx, records)
 }
 
-func (t *Table) Query(query string, args ...interface{}) (*Rows, error) {
-	rows, err := t.exe.Query(query, args...)
-	return (*Rows)(rows), err
-}*/
+func (t *Table) SelectOne(record interface{}, where interface{}, args ...interface{}) error {
+	return t.SelectOneContext(context.Background(), record, where, args...)
+}
+
+func (t *Table) SelectOneContext(ctx context.Context, record interface{}, where interface{}, args ...interface{}) error {
+	return t.Where(where, args...).SelectOneContext(ctx, record)
+}
 
-func (t *Table) Delete(where string, args ...interface{}) error {
-	if len(where) == 0 {
+// Delete deletes rows matching where, and returns number of affected rows.
+// Rows are marked as deleted if record type of table has soft delete column. Table created by name, e.g. db.Table("products"),
+// knows record type once it's used or registered by Register
+func (t *Table) Delete(where interface{}, args ...interface{}) (int64, error) {
+	return t.DeleteContext(context.Background(), where, args...)
+}
+
+func (t *Table) DeleteContext(ctx context.Context, where interface{}, args ...interface{}) (int64, error) {
+	if where == nil || where == "" {
 		panic("where is empty")
 	}
-	var buf bytes.Buffer
-	buf.WriteString("DELETE FROM ")
-	buf.WriteString(t.name)
-	buf.WriteString(" WHERE ")
-	buf.WriteString(where)
+	return t.Where(where, args...).DeleteContext(ctx)
+}
 
-	query := buf.String()
+func (t *Table) Count(where interface{}, args ...interface{}) (int, error) {
+	return t.CountContext(context.Background(), where, args...)
+}
+
+func (t *Table) CountContext(ctx context.Context, where interface{}, args ...interface{}) (int, error) {
+	return t.Where(where, args...).CountContext(ctx)
+}
 
+func (t *Table) exec(ctx context.Context, query string, args []interface{}) (sql.Result, error) {
+	query = bindVars(t.dialect, query)
+	args = encodeArgs(args)
 	if log.GetLevel() <= log.DebugLevel {
 		log.Debug(query, toReadableArgs(args))
 	}
-
-	_, err := t.exe.Exec(query, args...)
-	if err != nil {
-		log.Error(err)
-	}
-	return err
+	return t.exe.ExecContext(ctx, query, args...)
 }
 
-func (t *Table) Count(where string, args ...interface{}) (int, error) {
-	var buf bytes.Buffer
-	buf.WriteString("SELECT COUNT(*) FROM ")
-	buf.WriteString(t.name)
-	if len(where) > 0 {
-		buf.WriteString(" WHERE ")
-		buf.WriteString(where)
-	}
-	query := buf.String()
-
+func (t *Table) query(ctx context.Context, query string, args []interface{}) (*sql.Rows, error) {
+	query = bindVars(t.dialect, query)
+	args = encodeArgs(args)
 	if log.GetLevel() <= log.DebugLevel {
 		log.Debug(query, toReadableArgs(args))
 	}
+	return t.exe.QueryContext(ctx, query, args...)
+}
 
-	var count int
-	err := t.exe.QueryRow(query, args...).Scan(&count)
-	if err != nil {
-		log.Error(err)
-		return 0, err
+func (t *Table) queryRow(ctx context.Context, query string, args []interface{}) *sql.Row {
+	query = bindVars(t.dialect, query)
+	args = encodeArgs(args)
+	if log.GetLevel() <= log.DebugLevel {
+		log.Debug(query, toReadableArgs(args))
 	}
-
-	return count, nil
+	return t.exe.QueryRowContext(ctx, query, args...)
 }
 
-func (t *Table) getFieldValueByName(item reflect.Value, info *columnInfo, name string) (interface{}, error) {
-	k := item.FieldByIndex(info.nameToIndex[name]).Interface()
+func getFieldValueByName(item reflect.Value, info *columnInfo, name string) (interface{}, error) {
+	field := item.FieldByIndex(info.nameToIndex[name])
 	if IndexOfString(info.jsonNames, name) >= 0 {
-		data, err := json.Marshal(k)
+		data, err := json.Marshal(field.Interface())
 		if err != nil {
 			return nil, err
 		}
@@ -593,13 +536,27 @@ func (t *Table) getFieldValueByName(item reflect.Value, info *columnInfo, name s
 		} else {
 			return data, nil
 		}
-	} else {
-		if IndexOfString(info.nullableNames, name) >= 0 && k == reflect.Zero(reflect.TypeOf(k)).Interface() {
+	}
+
+	if c := info.codecs[name]; c != nil {
+		if IndexOfString(info.nullableNames, name) >= 0 && field.IsZero() {
+			return nil, nil
+		}
+		return codecValue{v: field.Interface(), codec: c}.Value()
+	}
+
+	if field.Kind() == reflect.Ptr {
+		// Pointer is nullable naturally
+		if field.IsNil() {
 			return nil, nil
-		} else {
-			return k, nil
 		}
+		return fieldValue(field.Elem()), nil
+	}
+
+	if IndexOfString(info.nullableNames, name) >= 0 && field.IsZero() {
+		return nil, nil
 	}
+	return fieldValue(field), nil
 }
 
 func toReadableArgs(args []interface{}) []interface{} {
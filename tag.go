@@ -0,0 +1,178 @@
+package sql
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Options of sql tag
+const (
+	tagPrimaryKey    = "primary key"
+	tagAutoIncrement = "auto_increment"
+	tagJSON          = "json"
+	tagNullable      = "nullable"
+	tagVersion       = "version"
+	tagDeletedAt     = "deleted_at"
+	tagCreatedAt     = "created_at"
+	tagUpdatedAt     = "updated_at"
+	tagUnique        = "unique"
+	tagIndex         = "index"
+
+	tagType    = "type"
+	tagSize    = "size"
+	tagDefault = "default"
+	tagPrefix  = "prefix"
+	tagCodec   = "codec"
+)
+
+var _tagFlags = map[string]struct{}{
+	tagPrimaryKey:    {},
+	tagAutoIncrement: {},
+	tagJSON:          {},
+	tagNullable:      {},
+	tagVersion:       {},
+	tagDeletedAt:     {},
+	tagCreatedAt:     {},
+	tagUpdatedAt:     {},
+	tagUnique:        {},
+	tagIndex:         {},
+}
+
+var _tagKeys = map[string]struct{}{
+	tagType:    {},
+	tagSize:    {},
+	tagDefault: {},
+	tagPrefix:  {},
+	tagCodec:   {},
+}
+
+// _legacyTypeFlags are type keywords which were allowed in tag. They are equivalent to type=keyword
+var _legacyTypeFlags = map[string]struct{}{
+	"int":     {},
+	"bigint":  {},
+	"bool":    {},
+	"tinyint": {},
+	"double":  {},
+	"date":    {},
+}
+
+// columnTag is parsed sql tag in format of name,flag,key=value
+// Name is optional, and it's lower cased. Flags and keys are case insensitive, and values are kept as they are
+type columnTag struct {
+	name   string
+	ignore bool
+	flags  map[string]struct{}
+	values map[string]string
+}
+
+func (t *columnTag) has(flag string) bool {
+	_, ok := t.flags[flag]
+	return ok
+}
+
+func (t *columnTag) value(key string) (string, bool) {
+	v, ok := t.values[key]
+	return v, ok
+}
+
+// empty returns true if tag has no option
+func (t *columnTag) empty() bool {
+	return len(t.name) == 0 && !t.ignore && len(t.flags) == 0 && len(t.values) == 0
+}
+
+func parseTag(tag string) (*columnTag, error) {
+	t := &columnTag{
+		flags:  make(map[string]struct{}),
+		values: make(map[string]string),
+	}
+	tag = strings.TrimSpace(tag)
+	if tag == "-" {
+		t.ignore = true
+		return t, nil
+	}
+	if len(tag) == 0 {
+		return t, nil
+	}
+
+	segments, err := splitTag(tag)
+	if err != nil {
+		return nil, err
+	}
+	for i, s := range segments {
+		if len(s) == 0 {
+			continue
+		}
+
+		if kv := strings.SplitN(s, "=", 2); len(kv) == 2 {
+			key := strings.ToLower(strings.TrimSpace(kv[0]))
+			if _, ok := _tagKeys[key]; !ok {
+				return nil, fmt.Errorf("unknown option %q", key)
+			}
+			if _, ok := t.values[key]; ok {
+				return nil, fmt.Errorf("duplicate option %q", key)
+			}
+			t.values[key] = strings.TrimSpace(kv[1])
+			continue
+		}
+
+		// Collapse spaces, e.g. "primary  key"
+		flag := strings.ToLower(strings.Join(strings.Fields(s), " "))
+		if _, ok := _tagFlags[flag]; ok {
+			t.flags[flag] = struct{}{}
+			continue
+		}
+
+		if _, ok := _legacyTypeFlags[flag]; ok {
+			t.values[tagType] = strings.ToUpper(flag)
+			continue
+		}
+
+		if i == 0 && _regexpVariable.MatchString(flag) {
+			t.name = flag
+			continue
+		}
+
+		if i == 0 {
+			return nil, fmt.Errorf("invalid column name %q", s)
+		}
+		return nil, fmt.Errorf("unknown option %q", s)
+	}
+	return t, nil
+}
+
+// splitTag splits tag by commas which are not in quotes or parentheses, e.g. default='a,b',type=DECIMAL(10,2)
+func splitTag(tag string) ([]string, error) {
+	var segments []string
+	var quote byte
+	depth := 0
+	start := 0
+	for i := 0; i < len(tag); i++ {
+		c := tag[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+			if depth < 0 {
+				return nil, errors.New("unbalanced parentheses")
+			}
+		case c == ',' && depth == 0:
+			segments = append(segments, strings.TrimSpace(tag[start:i]))
+			start = i + 1
+		}
+	}
+	if quote != 0 {
+		return nil, errors.New("unterminated quote")
+	}
+	if depth != 0 {
+		return nil, errors.New("unbalanced parentheses")
+	}
+	return append(segments, strings.TrimSpace(tag[start:])), nil
+}
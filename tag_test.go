@@ -0,0 +1,93 @@
+package sql
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTag(t *testing.T) {
+	tag, err := parseTag("Txt, JSON,nullable")
+	require.NoError(t, err)
+	require.Equal(t, "txt", tag.name)
+	require.True(t, tag.has(tagJSON))
+	require.True(t, tag.has(tagNullable))
+
+	tag, err = parseTag("primary  key,auto_increment")
+	require.NoError(t, err)
+	require.Empty(t, tag.name)
+	require.True(t, tag.has(tagPrimaryKey))
+	require.True(t, tag.has(tagAutoIncrement))
+
+	tag, err = parseTag("jsonb_data")
+	require.NoError(t, err)
+	require.Equal(t, "jsonb_data", tag.name)
+	require.False(t, tag.has(tagJSON))
+
+	tag, err = parseTag("price,type=DECIMAL(10,2),default='a,b'")
+	require.NoError(t, err)
+	require.Equal(t, "price", tag.name)
+	v, _ := tag.value(tagType)
+	require.Equal(t, "DECIMAL(10,2)", v)
+	v, _ = tag.value(tagDefault)
+	require.Equal(t, "'a,b'", v)
+
+	tag, err = parseTag("bigint")
+	require.NoError(t, err)
+	v, _ = tag.value(tagType)
+	require.Equal(t, "BIGINT", v)
+
+	tag, err = parseTag("created_at")
+	require.NoError(t, err)
+	require.Empty(t, tag.name)
+	require.True(t, tag.has(tagCreatedAt))
+
+	tag, err = parseTag("-")
+	require.NoError(t, err)
+	require.True(t, tag.ignore)
+
+	for _, s := range []string{"name,nulable", "name,color=red", "name,size=1,size=2", "1name", "default='a", "type=DECIMAL(10"} {
+		_, err = parseTag(s)
+		require.Error(t, err, s)
+	}
+}
+
+func TestParseColumnInfo_Tag(t *testing.T) {
+	t.Run("UnknownOption", func(t *testing.T) {
+		type Product struct {
+			ID   int    `sql:"primary key"`
+			Name string `sql:"name,nulable"`
+		}
+		require.PanicsWithValue(t, `invalid sql tag of Product.Name: unknown option "nulable"`, func() {
+			parseColumnInfo(reflect.TypeOf(Product{}))
+		})
+	})
+
+	t.Run("Prefix", func(t *testing.T) {
+		type Address struct {
+			City   string
+			Street string `sql:"st"`
+		}
+		type User struct {
+			ID   int
+			Home Address  `sql:"prefix=home_"`
+			Work *Address `sql:"prefix=work_"`
+		}
+		info := parseColumnInfo(reflect.TypeOf(User{}))
+		require.Equal(t, []string{"id", "home_city", "home_st", "work_city", "work_st"}, info.names)
+		require.Equal(t, fieldIndex{2, 1}, info.nameToIndex["work_st"])
+	})
+}
+
+func TestParseColumnInfo_OptionTag(t *testing.T) {
+	type Product struct {
+		ID         int   `sql:"primary key"`
+		CreateTime int64 `sql:"created_at"`
+		Revision   int64 `sql:"version"`
+	}
+	info := parseColumnInfo(reflect.TypeOf(Product{}))
+	require.Equal(t, []string{"id", "create_time", "revision"}, info.names)
+	require.Equal(t, "create_time", info.createdName)
+	require.Equal(t, "revision", info.versionName)
+}
@@ -0,0 +1,66 @@
+package sql
+
+import (
+	"reflect"
+	"time"
+)
+
+func (t *Table) now() time.Time {
+	if t.clock == nil {
+		return time.Now()
+	}
+	return t.clock.Now()
+}
+
+// timestampValue returns value of timestamp column typ at time now
+func timestampValue(typ reflect.Type, now time.Time) reflect.Value {
+	if typ == _timeType {
+		return reflect.ValueOf(now)
+	}
+	return reflect.ValueOf(now.Unix()).Convert(typ)
+}
+
+// stampInsert fills created_at if it's zero, and updated_at of record v
+func (t *Table) stampInsert(v reflect.Value, info *columnInfo) {
+	if len(info.createdName) == 0 && len(info.updatedName) == 0 {
+		return
+	}
+	now := t.now()
+	if len(info.createdName) > 0 {
+		f := v.FieldByIndex(info.nameToIndex[info.createdName])
+		if f.IsZero() {
+			f.Set(timestampValue(f.Type(), now))
+		}
+	}
+	t.stampUpdate(v, info, now)
+}
+
+// stampUpdate sets updated_at of record v
+func (t *Table) stampUpdate(v reflect.Value, info *columnInfo, now time.Time) {
+	if len(info.updatedName) == 0 {
+		return
+	}
+	f := v.FieldByIndex(info.nameToIndex[info.updatedName])
+	f.Set(timestampValue(f.Type(), now))
+}
+
+// deletedAtValue returns value of soft delete column at time now, which is time.Time or unix time by type of the column
+func deletedAtValue(info *columnInfo, typ reflect.Type, now time.Time) interface{} {
+	f := typ.FieldByIndex(info.nameToIndex[info.deletedName])
+	if f.Type == _timeType || f.Type.Kind() == reflect.Ptr {
+		return now
+	}
+	return now.Unix()
+}
+
+// setDeletedAt sets soft delete column of record v to time now
+func setDeletedAt(v reflect.Value, info *columnInfo, now time.Time) {
+	f := v.FieldByIndex(info.nameToIndex[info.deletedName])
+	if f.Kind() == reflect.Ptr {
+		p := reflect.New(f.Type().Elem())
+		p.Elem().Set(reflect.ValueOf(now))
+		f.Set(p)
+		return
+	}
+	f.Set(timestampValue(f.Type(), now))
+}
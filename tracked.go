@@ -0,0 +1,109 @@
+package sql
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"reflect"
+)
+
+// Tracked records a snapshot of column values when a record is loaded or written.
+// Embed it into a struct, then Update writes changed columns only, and skips statement if nothing changed
+//
+//	type Product struct {
+//		sql.Tracked
+//		ID    int `sql:"primary key,auto_increment"`
+//		Price float32
+//	}
+type Tracked struct {
+	snapshot map[string]interface{}
+}
+
+// ResetTracking drops the snapshot, so that next Update writes all columns
+func (t *Tracked) ResetTracking() {
+	t.snapshot = nil
+}
+
+func (t *Tracked) tracked() *Tracked {
+	return t
+}
+
+type tracker interface {
+	tracked() *Tracked
+}
+
+// getTracked returns embedded Tracked of struct value v, or nil if v doesn't embed it
+func getTracked(v reflect.Value) *Tracked {
+	if !v.CanAddr() {
+		return nil
+	}
+	if t, ok := v.Addr().Interface().(tracker); ok {
+		return t.tracked()
+	}
+	return nil
+}
+
+// track records values of columns in v's snapshot. Snapshot is replaced rather than modified,
+// as copies of a record share the same map
+func track(v reflect.Value, info *columnInfo, columns []string) {
+	t := getTracked(v)
+	if t == nil {
+		return
+	}
+	snapshot := make(map[string]interface{}, len(info.names))
+	for k, val := range t.snapshot {
+		snapshot[k] = val
+	}
+	for _, name := range columns {
+		fv, err := snapshotValue(v, info, name)
+		if err != nil {
+			delete(snapshot, name)
+			continue
+		}
+		snapshot[name] = fv
+	}
+	t.snapshot = snapshot
+}
+
+// changedColumns returns columns whose values differ from snapshot.
+// ok is false if v isn't tracked, then all columns should be written
+func changedColumns(v reflect.Value, info *columnInfo, columns []string) (changed []string, ok bool) {
+	t := getTracked(v)
+	if t == nil || t.snapshot == nil {
+		return nil, false
+	}
+	for _, name := range columns {
+		old, found := t.snapshot[name]
+		if !found {
+			changed = append(changed, name)
+			continue
+		}
+		fv, err := snapshotValue(v, info, name)
+		if err != nil || !equalValue(old, fv) {
+			changed = append(changed, name)
+		}
+	}
+	return changed, true
+}
+
+// snapshotValue returns column value which doesn't change with record. Valuer is converted into driver value,
+// as it may be a pointer to the field
+func snapshotValue(v reflect.Value, info *columnInfo, name string) (interface{}, error) {
+	fv, err := getFieldValueByName(v, info, name)
+	if err != nil {
+		return nil, err
+	}
+	if valuer, ok := fv.(driver.Valuer); ok {
+		return valuer.Value()
+	}
+	return fv, nil
+}
+
+func equalValue(a, b interface{}) bool {
+	if ab, ok := a.([]byte); ok {
+		if bb, ok := b.([]byte); ok {
+			return bytes.Equal(ab, bb)
+		}
+		return false
+	}
+	return reflect.DeepEqual(a, b)
+}
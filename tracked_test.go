@@ -0,0 +1,54 @@
+package sql
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type trackedContent struct {
+	Title string
+}
+
+type trackedProduct struct {
+	Tracked
+	ID    int `sql:"primary key,auto_increment"`
+	Name  string
+	Price float32
+	Email string          `sql:"nullable"`
+	Text  *trackedContent `sql:"json"`
+}
+
+func TestChangedColumns(t *testing.T) {
+	p := &trackedProduct{ID: 1, Name: "apple", Text: &trackedContent{Title: "a"}}
+	v := reflect.ValueOf(p).Elem()
+	info := getColumnInfo(v.Type())
+	require.Equal(t, []string{"id", "name", "price", "email", "text"}, info.names)
+
+	_, ok := changedColumns(v, info, info.notPKNames)
+	require.False(t, ok)
+
+	track(v, info, info.names)
+	changed, ok := changedColumns(v, info, info.notPKNames)
+	require.True(t, ok)
+	require.Empty(t, changed)
+
+	copied := *p
+	p.Price = 0.2
+	p.Text.Title = "b"
+	changed, _ = changedColumns(v, info, info.notPKNames)
+	require.Equal(t, []string{"price", "text"}, changed)
+
+	track(v, info, changed)
+	changed, _ = changedColumns(v, info, info.notPKNames)
+	require.Empty(t, changed)
+
+	// Copy keeps its own snapshot
+	changed, _ = changedColumns(reflect.ValueOf(&copied).Elem(), info, info.notPKNames)
+	require.Equal(t, []string{"text"}, changed)
+
+	p.ResetTracking()
+	_, ok = changedColumns(v, info, info.notPKNames)
+	require.False(t, ok)
+}
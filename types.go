@@ -8,7 +8,7 @@ import (
 	"strings"
 
 	"github.com/gopub/conv"
-	"github.com/gopub/sql/pg"
+	"github.com/gopub/sql/internal/composite"
 	"github.com/gopub/types"
 	"github.com/shopspring/decimal"
 )
@@ -68,7 +68,7 @@ func (n *PhoneNumber) Scan(src interface{}) error {
 		return nil
 	}
 
-	fields, err := pg.ParseCompositeFields(s)
+	fields, err := composite.Parse(s)
 	if err != nil {
 		return fmt.Errorf("parse composite fields %s: %w", s, err)
 	}
@@ -176,7 +176,7 @@ func (m *Money) Scan(src interface{}) error {
 		return nil
 	}
 
-	fields, err := pg.ParseCompositeFields(s)
+	fields, err := composite.Parse(s)
 	if err != nil {
 		return fmt.Errorf("parse composite fields %s: %w", s, err)
 	}
@@ -224,7 +224,7 @@ func (p *Point) Scan(src interface{}) error {
 	if s == "" {
 		return nil
 	}
-	fields, err := pg.ParseCompositeFields(s)
+	fields, err := composite.Parse(s)
 	if err != nil {
 		return fmt.Errorf("parse composite fields %s: %w", s, err)
 	}
@@ -277,7 +277,7 @@ func (p *Place) Scan(src interface{}) error {
 	if s == "" {
 		return nil
 	}
-	fields, err := pg.ParseCompositeFields(s)
+	fields, err := composite.Parse(s)
 	if err != nil {
 		return fmt.Errorf("parse composite fields %s: %w", s, err)
 	}
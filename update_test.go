@@ -0,0 +1,123 @@
+package sql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time {
+	return time.Time(c)
+}
+
+type updateProduct struct {
+	ID        int `sql:"primary key"`
+	Name      string
+	Price     float64
+	Version   int64 `sql:"version"`
+	UpdatedAt int64 `sql:"updated_at"`
+}
+
+func (updateProduct) TableName() string {
+	return "products"
+}
+
+func openUpdateDB(t *testing.T) *DBWrapper {
+	db := openSQLite(t)
+	db.SetClock(fixedClock(time.Unix(100, 0)))
+	require.NoError(t, db.CreateTable(&updateProduct{}))
+	for i, name := range []string{"apple", "pear", "peach"} {
+		require.NoError(t, db.Insert(&updateProduct{ID: i + 1, Name: name, Price: float64(i + 1)}))
+	}
+	db.SetClock(fixedClock(time.Unix(200, 0)))
+	return db
+}
+
+func TestTable_UpdateColumns(t *testing.T) {
+	db := openUpdateDB(t)
+	p := &updateProduct{ID: 1, Name: "banana", Price: 0.5, Version: 1}
+	n, err := db.UpdateColumns(p, "price")
+	require.NoError(t, err)
+	require.Equal(t, int64(1), n)
+	require.Equal(t, int64(2), p.Version)
+	require.Equal(t, int64(200), p.UpdatedAt)
+
+	var got updateProduct
+	require.NoError(t, db.Get(&got, 1))
+	require.Equal(t, "apple", got.Name)
+	require.Equal(t, 0.5, got.Price)
+	require.Equal(t, int64(2), got.Version)
+	require.Equal(t, int64(200), got.UpdatedAt)
+
+	// Version 1 is stale
+	_, err = db.UpdateColumns(&updateProduct{ID: 1, Price: 0.6, Version: 1}, "price")
+	require.Equal(t, ErrStaleRecord, err)
+
+	require.Panics(t, func() {
+		db.UpdateColumns(p, "color")
+	})
+	require.Panics(t, func() {
+		db.UpdateColumns(p, "id")
+	})
+}
+
+func TestTable_UpdateWhere(t *testing.T) {
+	db := openUpdateDB(t)
+	tbl := db.Table(&updateProduct{})
+	n, err := tbl.UpdateWhere(map[string]interface{}{"price": 9.0}, "name IN (?)", []string{"pear", "peach"})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), n)
+
+	var l []*updateProduct
+	require.NoError(t, tbl.Where("price = ?", 9.0).OrderBy("id").Select(&l))
+	require.Len(t, l, 2)
+	for _, p := range l {
+		require.Equal(t, int64(200), p.UpdatedAt)
+	}
+
+	n, err = tbl.Where(Gt("price", 100)).Update(map[string]interface{}{"name": "x"})
+	require.NoError(t, err)
+	require.Zero(t, n)
+
+	// Table created by name doesn't stamp updated_at
+	n, err = db.Table("products").UpdateWhere(map[string]interface{}{"price": 1.0}, Eq("id", 1))
+	require.NoError(t, err)
+	require.Equal(t, int64(1), n)
+	var p updateProduct
+	require.NoError(t, db.Get(&p, 1))
+	require.Equal(t, int64(100), p.UpdatedAt)
+
+	_, err = tbl.Where("id = ?", 1).Limit(1).Update(map[string]interface{}{"price": 2.0})
+	require.Error(t, err)
+	require.Panics(t, func() {
+		tbl.UpdateWhere(map[string]interface{}{"price": 2.0}, "")
+	})
+}
+
+func TestQuery_Select(t *testing.T) {
+	db := openUpdateDB(t)
+	tbl := db.Table(&updateProduct{})
+
+	var l []*updateProduct
+	require.NoError(t, tbl.Where("price > ?", 1).Where(Like("name", "pe%")).OrderBy("price DESC").Limit(1).Select(&l))
+	require.Len(t, l, 1)
+	require.Equal(t, "peach", l[0].Name)
+
+	// Select appends records
+	l = nil
+	require.NoError(t, tbl.OrderBy("id").Offset(1).Select(&l))
+	require.Len(t, l, 2)
+	require.Equal(t, 2, l[0].ID)
+
+	var p updateProduct
+	require.NoError(t, tbl.Where(Or(Eq("name", "pear"), Eq("name", "x"))).SelectOne(&p))
+	require.Equal(t, 2, p.ID)
+	require.Equal(t, ErrNoRows, tbl.Where("name = ?", "x").SelectOne(&p))
+
+	n, err := tbl.Where(In("id", []int{1, 2})).Count()
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+}